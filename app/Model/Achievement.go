@@ -1,36 +1,104 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+)
 
 type Attachment struct {
+	ID         string    `bson:"id,omitempty" json:"id"` // tetap sama walaupun file diganti
 	FileName   string    `bson:"fileName" json:"fileName"`
 	FileUrl    string    `bson:"fileUrl" json:"fileUrl"`
 	FileType   string    `bson:"fileType" json:"fileType"`
+	StorageKey string    `bson:"storageKey,omitempty" json:"-"` // key di BlobStore
+	Size       int64     `bson:"size,omitempty" json:"size,omitempty"`
+	SHA256     string    `bson:"sha256,omitempty" json:"sha256,omitempty"` // checksum isi file, untuk deduplikasi dan cek integritas
+	HasPreview bool      `bson:"hasPreview" json:"hasPreview"`
+	PreviewKey string    `bson:"previewKey,omitempty" json:"-"` // thumbnail JPEG di BlobStore
 	UploadedAt time.Time `bson:"uploadedAt" json:"uploadedAt"`
 }
 
-type CompetitionDetails struct {
-	CompetitionName  string    `bson:"competitionName" json:"competitionName"`
-	CompetitionLevel string    `bson:"competitionLevel" json:"competitionLevel"`
-	Rank             int       `bson:"rank" json:"rank"`
-	MedalType        string    `bson:"medalType" json:"medalType"`
-	EventDate        time.Time `bson:"eventDate" json:"eventDate"`
-	Location         string    `bson:"location" json:"location"`
-	Organizer        string    `bson:"organizer" json:"organizer"`
+// Key mengembalikan key BlobStore. Lampiran lama belum punya storageKey dan
+// disimpan di ./uploads dengan fileUrl "/uploads/<key>".
+func (a Attachment) Key() string {
+	if a.StorageKey != "" {
+		return a.StorageKey
+	}
+	return strings.TrimPrefix(a.FileUrl, "/uploads/")
+}
+
+// FileID adalah nama file di storage (<uuid>.<ext>)
+func (a Attachment) FileID() string {
+	return path.Base(a.Key())
+}
+
+// StableID adalah id lampiran di URL. Lampiran lama tanpa id memakai nama filenya,
+// yang juga tidak berubah karena penggantian file menyimpan id ini ke field ID.
+func (a Attachment) StableID() string {
+	if a.ID != "" {
+		return a.ID
+	}
+	return a.FileID()
+}
+
+func (a Attachment) MarshalJSON() ([]byte, error) {
+	type plain Attachment
+	p := plain(a)
+	p.ID = a.StableID()
+	return json.Marshal(p)
 }
 
 type Achievement struct {
 	ID              string `bson:"_id,omitempty" json:"id"`
 	StudentID       string `bson:"studentId" json:"studentId"`
-	AchievementType string `bson:"achievementType" json:"achievementType"`
-	Title           string `bson:"title" json:"title"`
-	Description     string `bson:"description" json:"description"`
+	AchievementType string `bson:"achievementType" json:"achievementType" validate:"required,oneof=competition publication certification organization academic"`
+	Title           string `bson:"title" json:"title" validate:"required,max=200"`
+	Description     string `bson:"description" json:"description" validate:"max=2000"`
 
-	Details     CompetitionDetails `bson:"details" json:"details"`
-	Attachments []Attachment       `bson:"attachments" json:"attachments"`
-	Tags        []string           `bson:"tags" json:"tags"`
+	// details divalidasi lewat Resolve karena isinya tergantung AchievementType
+	Details     AchievementDetails `bson:"details" json:"details" validate:"-"`
+	Attachments []Attachment       `bson:"attachments" json:"attachments" validate:"-"`
+	Tags        []string           `bson:"tags" json:"tags" validate:"max=20,dive,required,max=50"`
 	Points      int                `bson:"points" json:"points"`
 
+	// Salinan status dari PostgreSQL (achievement_references), ditulis oleh outbox worker
+	Status     string     `bson:"status,omitempty" json:"status,omitempty"`
+	VerifiedAt *time.Time `bson:"verifiedAt,omitempty" json:"verifiedAt,omitempty"`
+	StatusSeq  int64      `bson:"statusSeq,omitempty" json:"-"`
+
 	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
 	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
 }
+
+// AchievementVisibilityRequest: PUT /student/achievements/:id/visibility
+type AchievementVisibilityRequest struct {
+	IsPublic *bool `json:"is_public" validate:"required"`
+}
+
+// PublicAchievement adalah tampilan prestasi untuk pengunjung tanpa login,
+// tanpa id user pemilik dan data internal lain
+type PublicAchievement struct {
+	ID              string             `json:"id"`
+	AchievementType string             `json:"achievementType"`
+	Title           string             `json:"title"`
+	Description     string             `json:"description"`
+	Details         AchievementDetails `json:"details"`
+	Tags            []string           `json:"tags"`
+	Points          int                `json:"points"`
+	VerifiedAt      *time.Time         `json:"verifiedAt,omitempty"`
+}
+
+func NewPublicAchievement(a *Achievement, verifiedAt *time.Time) PublicAchievement {
+	return PublicAchievement{
+		ID:              a.ID,
+		AchievementType: a.AchievementType,
+		Title:           a.Title,
+		Description:     a.Description,
+		Details:         a.Details,
+		Tags:            a.Tags,
+		Points:          a.Points,
+		VerifiedAt:      verifiedAt,
+	}
+}
@@ -2,6 +2,15 @@ package model
 
 import "time"
 
+// Status prestasi di tabel achievement_references
+const (
+	StatusDraft     = "draft"
+	StatusSubmitted = "submitted"
+	StatusVerified  = "verified"
+	StatusRejected  = "rejected"
+	StatusDeleted   = "deleted"
+)
+
 type AchievementReference struct {
 	ID                 string     `json:"id"`
 	StudentID          string     `json:"student_id"`
@@ -11,17 +20,33 @@ type AchievementReference struct {
 	VerifiedAt         *time.Time `json:"verified_at"`
 	VerifiedBy         *string    `json:"verified_by"`
 	RejectionNote      *string    `json:"rejection_note"`
+	IsPublic           bool       `json:"is_public"` // tampil di portfolio publik, hanya untuk verified
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
 
+// AchievementHistory adalah satu entri timeline dari tabel achievement_status_events
 type AchievementHistory struct {
-	ID                 string     `json:"id"`
-	MongoAchievementID string     `json:"mongo_achievement_id"`
-	StudentID          string     `json:"student_id"`
-	Status             string     `json:"status"`
-	SubmittedAt        *time.Time `json:"submitted_at"`
-	VerifiedAt         *time.Time `json:"verified_at"`
-	VerifiedBy         *string    `json:"verified_by"`
-	RejectionReason    *string    `json:"rejection_reason"`
+	ID                 int64     `json:"id"`
+	MongoAchievementID string    `json:"mongo_achievement_id"`
+	StudentID          string    `json:"student_id"`
+	StudentUserID      string    `json:"-"` // users.id pemilik, untuk cek akses
+	FromStatus         *string   `json:"from_status"`
+	ToStatus           string    `json:"to_status"`
+	ActorID            *string   `json:"actor_id"`
+	ActorName          *string   `json:"actor_name"`
+	Note               *string   `json:"note"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// StatusTransition adalah perubahan status satu prestasi oleh seorang user
+type StatusTransition struct {
+	MongoAchievementID string
+	From               string
+	To                 string
+	ActorID            string
+	Note               *string // alasan penolakan
+	Points             *int    // poin hasil rubrik, hanya saat verified
+	// verify / reject: claim dosen lain yang diambil sebelum waktu ini sudah kedaluwarsa
+	ClaimedBefore time.Time
 }
@@ -0,0 +1,12 @@
+package model
+
+type Permission struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"` // contoh: achievement:verify
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+}
+
+type RolePermissionRequest struct {
+	PermissionID string `json:"permission_id" validate:"required,uuid"`
+}
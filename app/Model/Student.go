@@ -12,5 +12,5 @@ type Student struct {
 }
 
 type StudentAdvisorRequest struct {
-	AdvisorId string `json:"advisor_id"`
+	AdvisorId string `json:"advisor_id" validate:"required,uuid"`
 }
@@ -1,5 +1,12 @@
 package model
 
+// Nama role sesuai kolom roles.name
+const (
+	RoleAdmin    = "admin"
+	RoleStudent  = "mahasiswa"
+	RoleLecturer = "dosen"
+)
+
 type User struct {
 	ID        string `json:"id"`
 	Username  string `json:"username"`
@@ -16,5 +23,52 @@ type Profile struct {
 	Email    string `json:"email"`
 }
 
+type StudentProfileRequest struct {
+	AcademicYear string  `json:"academic_year" validate:"required,max=20"`
+	ProgramStudy string  `json:"program_study" validate:"required,max=100"`
+	AdvisorID    *string `json:"advisor_id,omitempty" validate:"omitempty,uuid"`
+}
+
+type LecturerProfileRequest struct {
+	Department string `json:"department" validate:"required,max=100"`
+}
+
+// CreateUserRequest dipakai admin untuk membuat user sekaligus profil sesuai role-nya
+type CreateUserRequest struct {
+	Username string                  `json:"username" validate:"required,min=3,max=50"`
+	Email    string                  `json:"email" validate:"required,email,max=100"`
+	Password string                  `json:"password_hash" validate:"required,min=8,max=72"` // batas input bcrypt
+	RoleID   string                  `json:"role_id" validate:"required,uuid"`
+	Fullname string                  `json:"full_name" validate:"max=100"`
+	Student  *StudentProfileRequest  `json:"student,omitempty" validate:"omitempty"`  // wajib jika role mahasiswa
+	Lecturer *LecturerProfileRequest `json:"lecturer,omitempty" validate:"omitempty"` // wajib jika role dosen
+}
+
+type UpdateUserRequest struct {
+	Name  string `json:"name" validate:"required,min=3,max=50"` // disimpan sebagai username
+	Email string `json:"email" validate:"required,email,max=100"`
+}
+
+type StudentProfile struct {
+	ID           string  `json:"id"`
+	AcademicYear string  `json:"academic_year"`
+	ProgramStudy string  `json:"program_study"`
+	AdvisorID    *string `json:"advisor_id"`
+}
 
+type LecturerProfile struct {
+	ID         string `json:"id"`
+	Department string `json:"department"`
+}
 
+type UserProfileResponse struct {
+	ID       string           `json:"id"`
+	Username string           `json:"username"`
+	Email    string           `json:"email"`
+	Fullname string           `json:"full_name"`
+	RoleID   string           `json:"role_id"`
+	Role     string           `json:"role"`
+	IsActive bool             `json:"is_active"`
+	Student  *StudentProfile  `json:"student,omitempty"`
+	Lecturer *LecturerProfile `json:"lecturer,omitempty"`
+}
@@ -0,0 +1,249 @@
+package model
+
+import (
+	"PROJECTUAS_BE/app/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Tipe prestasi yang didukung, sekaligus discriminator untuk AchievementDetails
+const (
+	TypeCompetition   = "competition"
+	TypePublication   = "publication"
+	TypeCertification = "certification"
+	TypeOrganization  = "organization"
+	TypeAcademic      = "academic" // beasiswa, penghargaan akademik, pertukaran pelajar
+)
+
+var AchievementTypes = []string{TypeCompetition, TypePublication, TypeCertification, TypeOrganization, TypeAcademic}
+
+// DetailsValue adalah isi details untuk satu tipe prestasi
+type DetailsValue interface {
+	Validate() error
+}
+
+type CompetitionDetails struct {
+	CompetitionName  string    `bson:"competitionName" json:"competitionName" validate:"required,max=200"`
+	CompetitionLevel string    `bson:"competitionLevel" json:"competitionLevel" validate:"required,max=50"`
+	Rank             int       `bson:"rank" json:"rank" validate:"min=0"`
+	MedalType        string    `bson:"medalType" json:"medalType" validate:"max=50"`
+	EventDate        time.Time `bson:"eventDate" json:"eventDate" validate:"required,notfuture"`
+	Location         string    `bson:"location" json:"location" validate:"max=200"`
+	Organizer        string    `bson:"organizer" json:"organizer" validate:"max=200"`
+}
+
+type PublicationDetails struct {
+	PublicationType  string    `bson:"publicationType" json:"publicationType" validate:"required,oneof=journal conference book"`
+	PublicationTitle string    `bson:"publicationTitle" json:"publicationTitle" validate:"required,max=300"`
+	Authors          []string  `bson:"authors" json:"authors" validate:"min=1,dive,required,max=100"`
+	Publisher        string    `bson:"publisher" json:"publisher" validate:"max=200"`
+	Identifier       string    `bson:"identifier" json:"identifier" validate:"max=50"` // ISSN / ISBN
+	DOI              string    `bson:"doi" json:"doi" validate:"max=100"`
+	PublishedAt      time.Time `bson:"publishedAt" json:"publishedAt" validate:"omitempty,notfuture"`
+}
+
+type CertificationDetails struct {
+	CertificationName   string     `bson:"certificationName" json:"certificationName" validate:"required,max=200"`
+	IssuedBy            string     `bson:"issuedBy" json:"issuedBy" validate:"required,max=200"`
+	CertificationNumber string     `bson:"certificationNumber" json:"certificationNumber" validate:"max=100"`
+	IssuedAt            time.Time  `bson:"issuedAt" json:"issuedAt" validate:"omitempty,notfuture"`
+	ValidUntil          *time.Time `bson:"validUntil,omitempty" json:"validUntil,omitempty" validate:"omitempty,gtfield=IssuedAt"`
+}
+
+type OrganizationDetails struct {
+	OrganizationName string     `bson:"organizationName" json:"organizationName" validate:"required,max=200"`
+	Position         string     `bson:"position" json:"position" validate:"required,max=100"`
+	PeriodStart      time.Time  `bson:"periodStart" json:"periodStart" validate:"required,notfuture"`
+	PeriodEnd        *time.Time `bson:"periodEnd,omitempty" json:"periodEnd,omitempty" validate:"omitempty,gtefield=PeriodStart"` // nil = masih aktif
+}
+
+type AcademicDetails struct {
+	AwardName   string    `bson:"awardName" json:"awardName" validate:"required,max=200"`
+	Category    string    `bson:"category" json:"category" validate:"omitempty,oneof=scholarship award exchange"`
+	Institution string    `bson:"institution" json:"institution" validate:"required,max=200"`
+	Level       string    `bson:"level" json:"level" validate:"max=50"`
+	AwardedAt   time.Time `bson:"awardedAt" json:"awardedAt" validate:"omitempty,notfuture"`
+}
+
+// Validate memakai tag `validate`, hasilnya validation.Errors dengan path relatif terhadap details
+
+func (d *CompetitionDetails) Validate() error   { return validation.Struct(d) }
+func (d *PublicationDetails) Validate() error   { return validation.Struct(d) }
+func (d *CertificationDetails) Validate() error { return validation.Struct(d) }
+func (d *OrganizationDetails) Validate() error  { return validation.Struct(d) }
+func (d *AcademicDetails) Validate() error      { return validation.Struct(d) }
+
+func newDetailsValue(detailsType string) (DetailsValue, error) {
+	switch detailsType {
+	case TypeCompetition:
+		return &CompetitionDetails{}, nil
+	case TypePublication:
+		return &PublicationDetails{}, nil
+	case TypeCertification:
+		return &CertificationDetails{}, nil
+	case TypeOrganization:
+		return &OrganizationDetails{}, nil
+	case TypeAcademic:
+		return &AcademicDetails{}, nil
+	}
+	return nil, fmt.Errorf("unknown achievement type %q", detailsType)
+}
+
+// AchievementDetails adalah details polimorfik. Di JSON maupun MongoDB disimpan sebagai
+// satu object dengan field "type" sebagai discriminator, contoh:
+//
+//	{"type": "publication", "publicationTitle": "...", "authors": ["..."]}
+type AchievementDetails struct {
+	Type  string
+	Value DetailsValue
+
+	raw json.RawMessage // details dari request tanpa "type", di-decode oleh Resolve
+}
+
+func NewAchievementDetails(value DetailsValue) AchievementDetails {
+	d := AchievementDetails{Value: value}
+	switch value.(type) {
+	case *CompetitionDetails:
+		d.Type = TypeCompetition
+	case *PublicationDetails:
+		d.Type = TypePublication
+	case *CertificationDetails:
+		d.Type = TypeCertification
+	case *OrganizationDetails:
+		d.Type = TypeOrganization
+	case *AcademicDetails:
+		d.Type = TypeAcademic
+	}
+	return d
+}
+
+// Resolve memastikan details sesuai achievementType. Request boleh mengirim details
+// tanpa "type", dalam hal ini achievementType yang dipakai sebagai discriminator.
+func (d *AchievementDetails) Resolve(achievementType string) error {
+	if d.Type == "" && d.raw != nil {
+		value, err := newDetailsValue(achievementType)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(d.raw, value); err != nil {
+			return fmt.Errorf("invalid details: %v", err)
+		}
+		d.Type, d.Value, d.raw = achievementType, value, nil
+	}
+
+	if d.Value == nil {
+		return errors.New("details is required")
+	}
+	if d.Type != achievementType {
+		return fmt.Errorf("details type %q does not match achievementType %q", d.Type, achievementType)
+	}
+
+	return d.Value.Validate()
+}
+
+// Competition mengembalikan details lomba, atau nilai kosong untuk tipe lain
+func (d AchievementDetails) Competition() CompetitionDetails {
+	if c, ok := d.Value.(*CompetitionDetails); ok && c != nil {
+		return *c
+	}
+	return CompetitionDetails{}
+}
+
+func (d AchievementDetails) MarshalJSON() ([]byte, error) {
+	if d.Value == nil {
+		return []byte("null"), nil
+	}
+
+	body, err := json.Marshal(d.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	fields["type"], _ = json.Marshal(d.Type)
+
+	return json.Marshal(fields)
+}
+
+func (d *AchievementDetails) UnmarshalJSON(data []byte) error {
+	*d = AchievementDetails{}
+	if string(data) == "null" {
+		return nil
+	}
+
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return err
+	}
+
+	if head.Type == "" {
+		d.raw = append(json.RawMessage{}, data...)
+		return nil
+	}
+
+	value, err := newDetailsValue(head.Type)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return err
+	}
+
+	d.Type, d.Value = head.Type, value
+	return nil
+}
+
+func (d AchievementDetails) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if d.Value == nil {
+		return bson.MarshalValue(nil)
+	}
+
+	body, err := bson.Marshal(d.Value)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var fields bson.D
+	if err := bson.Unmarshal(body, &fields); err != nil {
+		return 0, nil, err
+	}
+
+	return bson.MarshalValue(append(bson.D{{Key: "type", Value: d.Type}}, fields...))
+}
+
+// Dokumen lama tidak punya details.type dan selalu berisi data lomba
+func (d *AchievementDetails) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	*d = AchievementDetails{}
+	if t == bsontype.Null || t == bsontype.Undefined {
+		return nil
+	}
+	if t != bsontype.EmbeddedDocument {
+		return fmt.Errorf("cannot decode %v into achievement details", t)
+	}
+
+	detailsType := TypeCompetition
+	if v, ok := bson.Raw(data).Lookup("type").StringValueOK(); ok && v != "" {
+		detailsType = v
+	}
+
+	value, err := newDetailsValue(detailsType)
+	if err != nil {
+		return err
+	}
+	if err := bson.Unmarshal(data, value); err != nil {
+		return err
+	}
+
+	d.Type, d.Value = detailsType, value
+	return nil
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Jenis ketidaksesuaian antara MongoDB (achievements) dan PostgreSQL (achievement_references)
+const (
+	IssueMissingDocument  = "missing_document"  // reference aktif tanpa dokumen Mongo
+	IssueMissingReference = "missing_reference" // dokumen Mongo tanpa reference
+	IssueDeletedReference = "deleted_reference" // reference sudah deleted tapi dokumen Mongo masih ada
+)
+
+type ConsistencyIssue struct {
+	Kind               string `json:"kind"`
+	MongoAchievementID string `json:"mongo_achievement_id"`
+	StudentID          string `json:"student_id,omitempty"`
+	Status             string `json:"status,omitempty"`
+	Action             string `json:"action"`
+	Repaired           bool   `json:"repaired"`
+	Error              string `json:"error,omitempty"`
+}
+
+type ConsistencyReport struct {
+	CheckedAt      time.Time          `json:"checked_at"`
+	Repair         bool               `json:"repair"`
+	MongoDocuments int                `json:"mongo_documents"`
+	References     int                `json:"references"`
+	Issues         []ConsistencyIssue `json:"issues"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+type LeaderboardFilter struct {
+	ProgramStudy    string
+	AcademicYear    string
+	AchievementType string
+	From            *time.Time
+	To              *time.Time
+	Page            int
+	Limit           int
+}
+
+// LeaderboardCandidate adalah satu prestasi verified beserta pemiliknya (dari PostgreSQL)
+type LeaderboardCandidate struct {
+	MongoAchievementID string
+	StudentID          string
+	UserID             string
+	FullName           string
+	ProgramStudy       string
+	AcademicYear       string
+}
+
+// StudentPoints adalah hasil agregasi poin per mahasiswa (dari MongoDB, key = users.id)
+type StudentPoints struct {
+	UserID       string `bson:"_id"`
+	Points       int    `bson:"points"`
+	Achievements int    `bson:"achievements"`
+}
+
+type LeaderboardEntry struct {
+	Rank         int    `json:"rank"`
+	UserID       string `json:"-"`
+	StudentID    string `json:"student_id,omitempty"`
+	FullName     string `json:"full_name,omitempty"`
+	ProgramStudy string `json:"program_study"`
+	AcademicYear string `json:"academic_year"`
+	Points       int    `json:"points"`
+	Achievements int    `json:"achievements"`
+	Anonymized   bool   `json:"anonymized,omitempty"`
+	IsMe         bool   `json:"is_me,omitempty"`
+}
+
+type Leaderboard struct {
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+	Total int                `json:"total"`
+	Data  []LeaderboardEntry `json:"data"`
+	Me    *LeaderboardEntry  `json:"me,omitempty"` // posisi mahasiswa yang login
+}
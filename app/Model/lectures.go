@@ -2,15 +2,15 @@ package model
 
 // Verify atau reject yang bisa dilakukan oleh dosen wali
 type VerifyRequest struct {
-	Status          string  `json:"status"` // verified | rejected
-	RejectionReason *string `json:"rejection_reason,omitempty"`
+	Status          string  `json:"status" validate:"required,oneof=verified rejected"`
+	RejectionReason *string `json:"rejection_reason,omitempty" validate:"required_if=Status rejected,omitempty,min=1,max=500"`
 }
 
 // verify atau reject yang bisa dilakukan oleh dosen wali
 
 // Verify atau reject yang bisa dilakukan oleh dosen wali
 type RejectRequest struct {
-	Reason string `json:"reason"`
+	Reason string `json:"reason" validate:"required,max=500"`
 }
 
 // verify atau reject yang bisa dilakukan oleh dosen wali
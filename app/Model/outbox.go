@@ -0,0 +1,35 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Jenis pesan di achievement_outbox
+const (
+	OutboxStatusSync = "achievement.status_sync"
+	OutboxPointsSync = "achievement.points_sync"
+)
+
+// OutboxMessage adalah perubahan yang harus diterapkan ke MongoDB setelah transaksi PostgreSQL commit
+type OutboxMessage struct {
+	ID                 int64           `json:"id"`
+	IdempotencyKey     string          `json:"idempotency_key"`
+	MongoAchievementID string          `json:"mongo_achievement_id"`
+	EventType          string          `json:"event_type"`
+	Payload            json.RawMessage `json:"payload"`
+	Attempts           int             `json:"attempts"`
+	LastError          *string         `json:"last_error"`
+	CreatedAt          time.Time       `json:"created_at"`
+}
+
+// AchievementStatusSync adalah payload OutboxStatusSync.
+// Seq (id achievement_status_events) dipakai MongoDB untuk menolak pesan lama / duplikat.
+type AchievementStatusSync struct {
+	Seq           int64     `json:"seq"`
+	Status        string    `json:"status"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	VerifiedBy    *string   `json:"verified_by,omitempty"`
+	RejectionNote *string   `json:"rejection_note,omitempty"`
+	Points        *int      `json:"points,omitempty"`
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ListQuery adalah parameter paginasi dan sorting untuk endpoint list.
+// Cursor (keyset) dipakai jika diisi, selain itu offset dari Page.
+type ListQuery struct {
+	Limit  int
+	Page   int
+	Cursor string
+	Sort   string // nama field di query string, di-whitelist oleh repository
+	Desc   bool
+}
+
+func (q ListQuery) Offset() int {
+	if q.Cursor != "" || q.Page < 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.Limit
+}
+
+// PageInfo dikirim sebagai "meta" di response list
+type PageInfo struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Page       int    `json:"page,omitempty"` // hanya untuk mode offset
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Next       string `json:"next,omitempty"` // link halaman berikutnya, diisi service
+}
+
+// Cursor menyimpan nilai field sort dan id item terakhir dari halaman sebelumnya
+type Cursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func EncodeCursor(value string, id string) string {
+	raw, _ := json.Marshal(Cursor{Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// AchievementFilter: filter GET /achievements (MongoDB)
+type AchievementFilter struct {
+	Status          string
+	AchievementType string
+	Tags            []string // semua tag harus ada
+	StudentID       string   // users.id pemilik prestasi
+	Owners          []string // batas visibilitas pemanggil (users.id), nil = semua
+	From            *time.Time
+	To              *time.Time
+}
+
+// StudentFilter: filter GET /students
+type StudentFilter struct {
+	ProgramStudy string
+	AcademicYear string
+	Owners       []string // batas visibilitas pemanggil (users.id), nil = semua
+}
+
+// UserFilter: filter GET /admin/users
+type UserFilter struct {
+	Role     string // roles.name
+	IsActive *bool
+}
+
+// LecturerFilter: filter GET /lecturers
+type LecturerFilter struct {
+	Department string
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// PortfolioProfile: data mahasiswa yang boleh tampil di portfolio publik
+type PortfolioProfile struct {
+	StudentID    string `json:"-"`
+	UserID       string `json:"-"`
+	Username     string `json:"username"`
+	FullName     string `json:"full_name"`
+	ProgramStudy string `json:"program_study"`
+	AcademicYear string `json:"academic_year"`
+}
+
+// VerificationRecord adalah bukti verifikasi satu prestasi dari achievement_references
+type VerificationRecord struct {
+	MongoAchievementID string
+	StudentName        string
+	VerifiedAt         time.Time
+	VerifierName       string // nama dosen yang memverifikasi
+	Points             *int
+	IsPublic           bool
+}
+
+// PortfolioAchievement: prestasi di portfolio beserta lampiran dan link verifikasinya
+type PortfolioAchievement struct {
+	PublicAchievement
+	Attachments     []Attachment `json:"attachments"`
+	VerifiedBy      string       `json:"verifiedBy"`
+	VerificationURL string       `json:"verificationUrl"`
+}
+
+// Portfolio: response GET /public/portfolio/:username
+type Portfolio struct {
+	Student      PortfolioProfile       `json:"student"`
+	TotalPoints  int                    `json:"total_points"`
+	Achievements []PortfolioAchievement `json:"achievements"`
+}
+
+// AchievementVerification: response link verifikasi untuk pihak ketiga
+type AchievementVerification struct {
+	AchievementID   string    `json:"achievement_id"`
+	Title           string    `json:"title"`
+	AchievementType string    `json:"achievement_type"`
+	StudentName     string    `json:"student_name"`
+	Status          string    `json:"status"`
+	VerifiedAt      time.Time `json:"verified_at"`
+	VerifiedBy      string    `json:"verified_by"`
+}
@@ -2,21 +2,54 @@ package model
 
 import "time"
 
+// Periode time series statistik
+const (
+	PeriodMonth    = "month"    // 2025-03
+	PeriodSemester = "semester" // 2025-S1 (Jan–Jun), 2025-S2 (Jul–Des)
+)
+
 type AchievementStatistics struct {
 	Total     int            `json:"total"`
 	Submitted int            `json:"submitted"`
 	Verified  int            `json:"verified"`
 	Rejected  int            `json:"rejected"`
 	ByType    map[string]int `json:"by_type"`
+	ByLevel   map[string]int `json:"by_level"`
+	ByMedal   map[string]int `json:"by_medal"`
+	ByStatus  map[string]int `json:"by_status"`
+
+	Period string            `json:"period"`
+	From   *time.Time        `json:"from"`
+	To     *time.Time        `json:"to"`
+	Series []StatisticsPoint `json:"series"`
+}
+
+type StatisticsPoint struct {
+	Period   string         `json:"period"`
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+}
+
+// StatisticsQuery adalah input agregasi MongoDB.
+// Statuses berisi mongo_achievement_id → status dari PostgreSQL; hanya dokumen ini yang dihitung.
+type StatisticsQuery struct {
+	Statuses map[string]string
+	From     *time.Time
+	To       *time.Time
+	Period   string
 }
 
 type StudentAchievementItem struct {
-	MongoAchievementID string     `json:"mongo_achievement_id"`
-	Title              string     `json:"title"`
-	AchievementType    string     `json:"achievement_type"`
-	Status             string     `json:"status"`
-	SubmittedAt        *time.Time `json:"submitted_at"`
-	VerifiedAt         *time.Time `json:"verified_at"`
+	MongoAchievementID string              `json:"mongo_achievement_id"`
+	Title              string              `json:"title"`
+	AchievementType    string              `json:"achievement_type"`
+	Points             int                 `json:"points"`
+	Tags               []string            `json:"tags"`
+	Details            *AchievementDetails `json:"details"`
+	Status             string              `json:"status"`
+	SubmittedAt        *time.Time          `json:"submitted_at"`
+	VerifiedAt         *time.Time          `json:"verified_at"`
+	Missing            bool                `json:"missing,omitempty"` // dokumen MongoDB tidak ditemukan
 }
 
 type StudentReport struct {
@@ -25,5 +58,6 @@ type StudentReport struct {
 	Submitted    int                      `json:"submitted"`
 	Verified     int                      `json:"verified"`
 	Rejected     int                      `json:"rejected"`
+	TotalPoints  int                      `json:"total_points"` // hanya prestasi verified
 	Achievements []StudentAchievementItem `json:"achievements"`
 }
@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// ReviewQueueItem: satu prestasi submitted di antrean review dosen wali (GET /lecturer/queue)
+type ReviewQueueItem struct {
+	ReferenceID        string       `json:"reference_id"`
+	MongoAchievementID string       `json:"mongo_achievement_id"`
+	StudentID          string       `json:"student_id"` // students.id
+	StudentUserID      string       `json:"student_user_id"`
+	StudentName        string       `json:"student_name"`
+	StudentUsername    string       `json:"student_username"`
+	SubmittedAt        time.Time    `json:"submitted_at"`
+	ClaimedBy          *string      `json:"claimed_by"` // users.id dosen yang sedang mereview
+	ClaimedAt          *time.Time   `json:"claimed_at"`
+	ClaimedUntil       *time.Time   `json:"claimed_until,omitempty"` // diisi service dari ClaimLease
+	WaitingHours       int          `json:"waiting_hours"`
+	SLADueAt           time.Time    `json:"sla_due_at"`
+	Overdue            bool         `json:"overdue"`     // menunggu lebih lama dari SLA review
+	Achievement        *Achievement `json:"achievement"` // nil jika dokumen MongoDB tidak ditemukan
+}
+
+// ReviewQueueFilter: filter GET /lecturer/queue
+type ReviewQueueFilter struct {
+	LecturerUserID string
+	OverdueBefore  *time.Time // hanya yang submitted sebelum waktu ini (?overdue=true)
+}
+
+// BulkDecisionRequest: POST /lecturer/queue/decisions, maksimal 100 item per request
+type BulkDecisionRequest struct {
+	Items []BulkDecisionItem `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+type BulkDecisionItem struct {
+	ID              string  `json:"id" validate:"required"` // mongo_achievement_id
+	Status          string  `json:"status" validate:"required,oneof=verified rejected"`
+	RejectionReason *string `json:"rejection_reason,omitempty" validate:"required_if=Status rejected,omitempty,min=1,max=500"`
+}
+
+// BulkDecisionResult: hasil per item, item yang gagal tidak membatalkan item lain
+type BulkDecisionResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Status  string `json:"status,omitempty"` // status baru jika berhasil
+	Points  *int   `json:"points,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// ScoringRule: poin untuk kombinasi tipe prestasi × tingkat lomba × peringkat / medali.
+// Field nil berarti berlaku untuk semua nilai.
+type ScoringRule struct {
+	ID               string    `json:"id"`
+	AchievementType  string    `json:"achievement_type"`
+	CompetitionLevel *string   `json:"competition_level"`
+	Rank             *int      `json:"rank"`
+	MedalType        *string   `json:"medal_type"`
+	Points           int       `json:"points"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type ScoringRuleRequest struct {
+	AchievementType  string  `json:"achievement_type" validate:"required,oneof=competition publication certification organization academic"`
+	CompetitionLevel *string `json:"competition_level" validate:"omitempty,min=1,max=50"`
+	Rank             *int    `json:"rank" validate:"omitempty,min=1"`
+	MedalType        *string `json:"medal_type" validate:"omitempty,min=1,max=50"`
+	Points           int     `json:"points" validate:"min=0,max=1000"`
+}
+
+// AchievementPointsSync adalah payload OutboxPointsSync (hasil recalculation).
+// Version mencegah hasil recalculation lama menimpa yang lebih baru.
+type AchievementPointsSync struct {
+	Points  int   `json:"points"`
+	Version int64 `json:"version"`
+}
+
+type RecalculationResult struct {
+	Checked int `json:"checked"`
+	Updated int `json:"updated"`
+	Missing int `json:"missing"` // reference verified tanpa dokumen MongoDB
+}
@@ -0,0 +1,24 @@
+package model
+
+// AchievementSearch: parameter GET /achievements/search
+type AchievementSearch struct {
+	Text string
+
+	// users.id pemilik prestasi yang boleh terlihat oleh pemanggil.
+	// nil = semua (admin), slice kosong = tidak ada yang terlihat.
+	Owners []string
+}
+
+// AchievementSearchHit adalah satu hasil pencarian, urut dari score tertinggi
+type AchievementSearchHit struct {
+	Achievement `bson:",inline"`
+	Score       float64           `bson:"score" json:"score"`
+	Highlights  []SearchHighlight `bson:"-" json:"highlights"`
+}
+
+// SearchHighlight: potongan teks field yang cocok, kata yang dicari dibungkus <mark>.
+// Teks di luar <mark> sudah di-escape sehingga aman dirender sebagai HTML.
+type SearchHighlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
@@ -0,0 +1,107 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAchievementDetails_JSONDiscriminator(t *testing.T) {
+	body := `{
+		"achievementType": "publication",
+		"title": "Paper",
+		"details": {"type": "publication", "publicationTitle": "Deep Learning", "publicationType": "journal", "authors": ["A"]}
+	}`
+
+	var a model.Achievement
+	if err := json.Unmarshal([]byte(body), &a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pub, ok := a.Details.Value.(*model.PublicationDetails)
+	if !ok || pub.PublicationTitle != "Deep Learning" {
+		t.Fatalf("expected publication details, got %#v", a.Details.Value)
+	}
+
+	out, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(out), `"type":"publication"`) {
+		t.Fatalf("expected type discriminator in %s", out)
+	}
+}
+
+func TestAchievementDetails_ResolveFromAchievementType(t *testing.T) {
+	body := `{"achievementType": "organization", "details": {"organizationName": "BEM", "position": "Ketua"}}`
+
+	var a model.Achievement
+	if err := json.Unmarshal([]byte(body), &a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// periodStart wajib untuk organisasi
+	if err := a.Details.Resolve(a.AchievementType); err == nil || !strings.Contains(err.Error(), "periodStart") {
+		t.Fatalf("expected periodStart validation error, got %v", err)
+	}
+}
+
+func TestAchievementDetails_TypeMismatch(t *testing.T) {
+	d := model.NewAchievementDetails(&model.AcademicDetails{AwardName: "Beasiswa", Institution: "Kampus"})
+
+	if err := d.Resolve(model.TypeCompetition); err == nil {
+		t.Fatal("expected mismatch error")
+	}
+}
+
+func TestAchievementDetails_BSONRoundTrip(t *testing.T) {
+	until := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	in := model.Achievement{
+		AchievementType: model.TypeCertification,
+		Details: model.NewAchievementDetails(&model.CertificationDetails{
+			CertificationName: "AWS",
+			IssuedBy:          "Amazon",
+			ValidUntil:        &until,
+		}),
+	}
+
+	raw, err := bson.Marshal(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v := bson.Raw(raw).Lookup("details", "type").StringValue(); v != model.TypeCertification {
+		t.Fatalf("expected details.type to be stored, got %q", v)
+	}
+
+	var out model.Achievement
+	if err := bson.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cert, ok := out.Details.Value.(*model.CertificationDetails)
+	if !ok || cert.IssuedBy != "Amazon" || !cert.ValidUntil.Equal(until) {
+		t.Fatalf("unexpected details: %#v", out.Details.Value)
+	}
+}
+
+func TestAchievementDetails_LegacyBSONIsCompetition(t *testing.T) {
+	raw, _ := bson.Marshal(bson.M{
+		"achievementType": "Lomba",
+		"details":         bson.M{"competitionName": "Gemastik", "rank": 2},
+	})
+
+	var out model.Achievement
+	if err := bson.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.Details.Type != model.TypeCompetition || out.Details.Competition().Rank != 2 {
+		t.Fatalf("expected legacy details decoded as competition, got %#v", out.Details)
+	}
+}
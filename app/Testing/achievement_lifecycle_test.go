@@ -0,0 +1,99 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/service"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+)
+
+func newLifecycle(db *sql.DB) *service.AchievementLifecycle {
+	return service.NewAchievementLifecycle(
+		repository.NewAchievementReferenceRepository(db),
+		repository.NewStudentRepository(db),
+	)
+}
+
+func referenceRows(status string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "student_id", "mongo_achievement_id", "status",
+		"submitted_at", "verified_at", "verified_by", "rejection_reason", "is_public",
+		"created_at", "updated_at",
+	}).AddRow(
+		"ref-1", "student-1", "mongo-1", status,
+		nil, nil, nil, nil, false,
+		time.Now(), time.Now(),
+	)
+}
+
+func expectConflict(t *testing.T, err error) {
+	t.Helper()
+
+	fiberErr, ok := err.(*fiber.Error)
+	if !ok || fiberErr.Code != fiber.StatusConflict {
+		t.Fatalf("expected 409 conflict, got %v", err)
+	}
+}
+
+func TestLifecycle_SubmitTwiceRejected(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM achievement_references`).
+		WithArgs("mongo-1").
+		WillReturnRows(referenceRows(model.StatusSubmitted))
+
+	_, err := newLifecycle(db).Apply(context.Background(), "mongo-1", service.ActionSubmit, "user-1", nil)
+
+	expectConflict(t, err)
+}
+
+func TestLifecycle_EditAfterVerifyRejected(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM achievement_references`).
+		WithArgs("mongo-1").
+		WillReturnRows(referenceRows(model.StatusVerified))
+
+	_, _, err := newLifecycle(db).Guard(context.Background(), "mongo-1", service.ActionEdit)
+
+	expectConflict(t, err)
+}
+
+func TestLifecycle_ResubmitAfterReject(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM achievement_references`).
+		WithArgs("mongo-1").
+		WillReturnRows(referenceRows(model.StatusRejected))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE achievement_references`).
+		WithArgs(model.StatusSubmitted, "mongo-1", model.StatusRejected).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ref-1"))
+	mock.ExpectQuery(`INSERT INTO achievement_status_events`).
+		WillReturnRows(statusEventRows(1))
+	expectOutbox(mock, "status-event:1")
+	mock.ExpectCommit()
+
+	ref, err := newLifecycle(db).Apply(context.Background(), "mongo-1", service.ActionSubmit, "user-1", nil)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ref.Status != model.StatusSubmitted {
+		t.Fatalf("expected status submitted, got %s", ref.Status)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
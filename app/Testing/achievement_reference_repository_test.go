@@ -0,0 +1,313 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func statusEventRows(id int64) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "created_at"}).AddRow(id, time.Now())
+}
+
+// setiap perubahan status juga menulis pesan outbox untuk MongoDB
+func expectOutbox(mock sqlmock.Sqlmock, key string) {
+	mock.ExpectExec(`INSERT INTO achievement_outbox`).
+		WithArgs(key, sqlmock.AnyArg(), model.OutboxStatusSync, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func TestCreateReference_Success(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAchievementReferenceRepository(db)
+
+	ref := &model.AchievementReference{
+		ID:                 "ref-1",
+		StudentID:          "student-1",
+		MongoAchievementID: "mongo-1",
+		Status:             model.StatusDraft,
+		SubmittedAt:        nil,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO achievement_references`).
+		WithArgs(
+			ref.ID,
+			ref.StudentID,
+			ref.MongoAchievementID,
+			ref.Status,
+			ref.SubmittedAt,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO achievement_status_events`).
+		WithArgs("ref-1", "mongo-1", sqlmock.AnyArg(), sqlmock.AnyArg(), model.StatusDraft, nil).
+		WillReturnRows(statusEventRows(1))
+	expectOutbox(mock, "status-event:1")
+	mock.ExpectCommit()
+
+	err := repo.Create(context.Background(), ref, "user-1")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateStatus_Verify(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAchievementReferenceRepository(db)
+
+	points := 50
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE achievement_references`).
+		WithArgs(
+			model.StatusVerified,
+			"lecturer-1",
+			nil,
+			"mongo-1",
+			model.StatusSubmitted,
+			&points,
+			sqlmock.AnyArg(),
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ref-1"))
+	mock.ExpectQuery(`INSERT INTO achievement_status_events`).
+		WithArgs("ref-1", "mongo-1", sqlmock.AnyArg(), sqlmock.AnyArg(), model.StatusVerified, nil).
+		WillReturnRows(statusEventRows(1))
+	expectOutbox(mock, "status-event:1")
+	mock.ExpectCommit()
+
+	err := repo.UpdateStatus(context.Background(), model.StatusTransition{
+		MongoAchievementID: "mongo-1",
+		From:               model.StatusSubmitted,
+		To:                 model.StatusVerified,
+		ActorID:            "lecturer-1",
+		Points:             &points,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateStatus_Reject(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAchievementReferenceRepository(db)
+
+	reason := "Dokumen tidak valid"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE achievement_references`).
+		WithArgs(
+			model.StatusRejected,
+			"lecturer-1",
+			&reason,
+			"mongo-1",
+			model.StatusSubmitted,
+			nil,
+			sqlmock.AnyArg(),
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ref-1"))
+	mock.ExpectQuery(`INSERT INTO achievement_status_events`).
+		WithArgs("ref-1", "mongo-1", sqlmock.AnyArg(), sqlmock.AnyArg(), model.StatusRejected, &reason).
+		WillReturnRows(statusEventRows(1))
+	expectOutbox(mock, "status-event:1")
+	mock.ExpectCommit()
+
+	err := repo.UpdateStatus(context.Background(), model.StatusTransition{
+		MongoAchievementID: "mongo-1",
+		From:               model.StatusSubmitted,
+		To:                 model.StatusRejected,
+		ActorID:            "lecturer-1",
+		Note:               &reason,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateStatus_StatusChanged(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAchievementReferenceRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE achievement_references`).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id`).
+		WithArgs("mongo-x", "lecturer-1", sqlmock.AnyArg()).
+		WillReturnRows(transitionCheckRows(true, false))
+	mock.ExpectRollback()
+
+	err := repo.UpdateStatus(context.Background(), model.StatusTransition{
+		MongoAchievementID: "mongo-x",
+		From:               model.StatusSubmitted,
+		To:                 model.StatusVerified,
+		ActorID:            "lecturer-1",
+	})
+
+	if !errors.Is(err, repository.ErrStatusChanged) {
+		t.Fatalf("expected status changed error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// transitionCheckRows: hasil query penjelasan saat UPDATE verify / reject tidak mengenai baris
+func transitionCheckRows(advisor bool, claimedByOther bool) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"advisor", "claimed"}).AddRow(advisor, claimedByOther)
+}
+
+func TestUpdateStatus_ClaimedByAnotherLecturer(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAchievementReferenceRepository(db)
+
+	claimedBefore := time.Now().Add(-30 * time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE achievement_references ar[\s\S]+claimed_by = NULL[\s\S]+ar.claimed_by IS NULL OR ar.claimed_by = \$2 OR ar.claimed_at < \$7`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id`).
+		WithArgs("mongo-1", "lecturer-1", claimedBefore).
+		WillReturnRows(transitionCheckRows(true, true))
+	mock.ExpectRollback()
+
+	err := repo.UpdateStatus(context.Background(), model.StatusTransition{
+		MongoAchievementID: "mongo-1",
+		From:               model.StatusSubmitted,
+		To:                 model.StatusVerified,
+		ActorID:            "lecturer-1",
+		ClaimedBefore:      claimedBefore,
+	})
+
+	if !errors.Is(err, repository.ErrReviewClaimed) {
+		t.Fatalf("expected claimed error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateStatus_VerifyByNonAdvisor(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAchievementReferenceRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE achievement_references ar[\s\S]+JOIN lecturers l ON l.id = s.advisor_id[\s\S]+AND l.user_id = \$2`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id`).
+		WithArgs("mongo-1", "lecturer-2", sqlmock.AnyArg()).
+		WillReturnRows(transitionCheckRows(false, false))
+	mock.ExpectRollback()
+
+	err := repo.UpdateStatus(context.Background(), model.StatusTransition{
+		MongoAchievementID: "mongo-1",
+		From:               model.StatusSubmitted,
+		To:                 model.StatusVerified,
+		ActorID:            "lecturer-2",
+	})
+
+	if !errors.Is(err, repository.ErrNotAdvisor) {
+		t.Fatalf("expected not advisor error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindByMongoID_NotFound(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAchievementReferenceRepository(db)
+
+	mock.ExpectQuery(`FROM achievement_references`).
+		WithArgs("mongo-x").
+		WillReturnError(sql.ErrNoRows)
+
+	ref, err := repo.FindByMongoID(context.Background(), "mongo-x")
+
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+
+	if ref != nil {
+		t.Fatal("expected nil reference")
+	}
+}
+
+func TestUpdatePoints_EnqueuesOutbox(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAchievementReferenceRepository(db)
+
+	updatedAt := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`points IS DISTINCT FROM \$1`).
+		WithArgs(75, "mongo-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "now"}).AddRow("ref-1", updatedAt))
+	mock.ExpectExec(`INSERT INTO achievement_outbox`).
+		WithArgs(fmt.Sprintf("points:ref-1:%d", repository.PointsVersion(updatedAt)), "mongo-1", model.OutboxPointsSync, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	changed, err := repo.UpdatePoints(context.Background(), "mongo-1", 75)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !changed {
+		t.Fatal("expected points to change")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdatePoints_Unchanged(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAchievementReferenceRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE achievement_references`).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	changed, err := repo.UpdatePoints(context.Background(), "mongo-1", 75)
+
+	if err != nil || changed {
+		t.Fatalf("expected unchanged without error, got %v %v", changed, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,61 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/service"
+	"strings"
+	"testing"
+)
+
+func TestBuildHighlights_MarksTermsPerField(t *testing.T) {
+	a := &model.Achievement{
+		Title: "Juara 1 Hackathon Nasional",
+		Details: model.NewAchievementDetails(&model.CompetitionDetails{
+			CompetitionName: "Gemastik Hackathon",
+			Organizer:       "Kemdikbud",
+		}),
+		Tags:        []string{"web", "hackathon"},
+		Description: "Membangun aplikasi <web> untuk UMKM",
+	}
+
+	highlights := service.BuildHighlights(a, `hackathon -gemastik "aplikasi <web>"`)
+
+	got := map[string]string{}
+	for _, h := range highlights {
+		got[h.Field] = h.Snippet
+	}
+
+	if got["title"] != "Juara 1 <mark>Hackathon</mark> Nasional" {
+		t.Fatalf("unexpected title snippet: %q", got["title"])
+	}
+	if got["details.competitionName"] != "Gemastik <mark>Hackathon</mark>" {
+		t.Fatalf("negated term must not be highlighted: %q", got["details.competitionName"])
+	}
+	if _, ok := got["details.organizer"]; ok {
+		t.Fatalf("organizer has no match, got %q", got["details.organizer"])
+	}
+	// frasa dicocokkan utuh dan teks asli di-escape
+	if got["description"] != "Membangun <mark>aplikasi &lt;web&gt;</mark> untuk UMKM" {
+		t.Fatalf("unexpected description snippet: %q", got["description"])
+	}
+}
+
+func TestBuildHighlights_TruncatesLongText(t *testing.T) {
+	a := &model.Achievement{
+		Title:       "Lomba",
+		Description: strings.Repeat("a ", 100) + "robotik" + strings.Repeat(" b", 100),
+	}
+
+	highlights := service.BuildHighlights(a, "robotik")
+	if len(highlights) != 1 {
+		t.Fatalf("expected 1 highlight, got %v", highlights)
+	}
+
+	snippet := highlights[0].Snippet
+	if !strings.HasPrefix(snippet, "…") || !strings.HasSuffix(snippet, "…") || !strings.Contains(snippet, "<mark>robotik</mark>") {
+		t.Fatalf("unexpected snippet: %q", snippet)
+	}
+	if len(snippet) > 2*60+len("<mark>robotik</mark>")+2*len("…") {
+		t.Fatalf("snippet too long: %d bytes", len(snippet))
+	}
+}
@@ -0,0 +1,98 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"context"
+)
+
+// stubAchievementRepository menggantikan MongoDB, hanya method yang di-override yang dipakai
+type stubAchievementRepository struct {
+	repository.AchievementRepository
+	owners   map[string]string
+	deleted  []string
+	synced   []model.AchievementStatusSync
+	syncErr  error
+	docs     []model.Achievement
+	query    *model.StatisticsQuery
+	points   []model.StudentPoints
+	byID     map[string]*model.Achievement
+	filter   *model.AchievementFilter
+	added    []model.Attachment
+	removed  []model.Attachment
+	replaced []model.Attachment
+}
+
+func (r *stubAchievementRepository) FindById(ctx context.Context, id string) (*model.Achievement, error) {
+	if a, ok := r.byID[id]; ok {
+		return a, nil
+	}
+	return nil, repository.NotFound("achievement not found")
+}
+
+func (r *stubAchievementRepository) AddAttachment(ctx context.Context, achievementID string, attachment model.Attachment, maxCount int) error {
+	r.added = append(r.added, attachment)
+	return nil
+}
+
+func (r *stubAchievementRepository) RemoveAttachment(ctx context.Context, achievementID string, attachment model.Attachment) error {
+	r.removed = append(r.removed, attachment)
+	return nil
+}
+
+func (r *stubAchievementRepository) ReplaceAttachment(ctx context.Context, achievementID string, old model.Attachment, replacement model.Attachment) error {
+	r.replaced = append(r.replaced, replacement)
+	return nil
+}
+
+func (r *stubAchievementRepository) ListAttachmentKeys(ctx context.Context) (map[string]bool, error) {
+	keys := map[string]bool{}
+	for _, a := range r.byID {
+		for _, att := range a.Attachments {
+			keys[att.Key()] = true
+			if att.PreviewKey != "" {
+				keys[att.PreviewKey] = true
+			}
+		}
+	}
+	return keys, nil
+}
+
+func (r *stubAchievementRepository) GetAchievementByID(id string) (*model.Achievement, error) {
+	return r.byID[id], nil
+}
+
+func (r *stubAchievementRepository) List(ctx context.Context, filter model.AchievementFilter, q model.ListQuery) ([]model.Achievement, model.PageInfo, error) {
+	r.filter = &filter
+	return r.docs, model.PageInfo{Limit: q.Limit, Page: q.Page, Total: int64(len(r.docs))}, nil
+}
+
+func (r *stubAchievementRepository) SumPointsByStudent(ctx context.Context, ids []string, filter model.LeaderboardFilter) ([]model.StudentPoints, error) {
+	return r.points, nil
+}
+
+func (r *stubAchievementRepository) AggregateStatistics(ctx context.Context, q model.StatisticsQuery) (*model.AchievementStatistics, error) {
+	r.query = &q
+	return &model.AchievementStatistics{Total: len(q.Statuses), Period: q.Period}, nil
+}
+
+func (r *stubAchievementRepository) FindByIDs(ctx context.Context, ids []string) ([]model.Achievement, error) {
+	return r.docs, nil
+}
+
+func (r *stubAchievementRepository) ApplyStatusSync(ctx context.Context, id string, sync model.AchievementStatusSync) error {
+	if r.syncErr != nil {
+		return r.syncErr
+	}
+	r.synced = append(r.synced, sync)
+	return nil
+}
+
+func (r *stubAchievementRepository) ListOwners(ctx context.Context) (map[string]string, error) {
+	return r.owners, nil
+}
+
+func (r *stubAchievementRepository) Delete(ctx context.Context, id string) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
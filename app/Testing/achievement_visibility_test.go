@@ -0,0 +1,157 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/service"
+	"PROJECTUAS_BE/middleware"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+)
+
+func newVisibilityApp(t *testing.T, claims *middleware.Claims, repo *stubAchievementRepository) (*fiber.App, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, _ := sqlmock.New()
+	t.Cleanup(func() { db.Close() })
+
+	svc := service.NewAchievementService(repo, newLifecycle(db), nil, nil, service.DefaultAttachmentPolicy())
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("claims", claims)
+		return c.Next()
+	})
+	app.Get("/achievements", svc.GetAllAchievements)
+	app.Get("/achievements/:id", svc.GetAchievementsByID)
+
+	return app, mock
+}
+
+func TestGetAchievementsByID_LecturerOnlyAdvisees(t *testing.T) {
+	repo := &stubAchievementRepository{byID: map[string]*model.Achievement{
+		"mine":  {ID: "mine", StudentID: "user-1"},
+		"other": {ID: "other", StudentID: "user-9"},
+	}}
+	app, mock := newVisibilityApp(t, &middleware.Claims{UserID: "lecturer-1", Role: middleware.RoleLecturer}, repo)
+
+	for id, expected := range map[string]int{"mine": fiber.StatusOK, "other": fiber.StatusForbidden} {
+		mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id`).
+			WithArgs("lecturer-1").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("user-1"))
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/achievements/"+id, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != expected {
+			t.Errorf("%s: expected %d, got %d", id, expected, resp.StatusCode)
+		}
+	}
+}
+
+func TestGetAllAchievements_StudentSeesOnlyOwn(t *testing.T) {
+	repo := &stubAchievementRepository{}
+	app, _ := newVisibilityApp(t, &middleware.Claims{UserID: "user-1", Role: middleware.RoleStudent}, repo)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/achievements?student=user-2", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	if repo.filter == nil || len(repo.filter.Owners) != 1 || repo.filter.Owners[0] != "user-1" {
+		t.Fatalf("expected owners restricted to user-1, got %+v", repo.filter)
+	}
+}
+
+func TestGetAllAchievements_AdminSeesAll(t *testing.T) {
+	repo := &stubAchievementRepository{}
+	app, _ := newVisibilityApp(t, &middleware.Claims{UserID: "admin-1", Role: middleware.RoleAdmin}, repo)
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/achievements", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	if repo.filter == nil || repo.filter.Owners != nil {
+		t.Fatalf("expected no owner restriction for admin, got %+v", repo.filter)
+	}
+}
+
+func TestSetPublic_OnlyVerified(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAchievementReferenceRepository(db)
+
+	mock.ExpectExec(`SET is_public = \$1`).
+		WithArgs(true, "mongo-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.SetPublic(context.Background(), "mongo-1", true)
+	if !errors.Is(err, repository.ErrStatusChanged) {
+		t.Fatalf("expected ErrStatusChanged, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLecturerStudents_OnlyAdvisees(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	svc := service.NewAStudentService(repository.NewStudentRepository(db), &stubAchievementRepository{}, newLifecycle(db))
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("claims", &middleware.Claims{UserID: "lecturer-1", Role: middleware.RoleLecturer})
+		return c.Next()
+	})
+	app.Get("/lecturer/students", svc.GetAllStudents)
+	app.Get("/lecturer/students/:id", svc.GetStudent)
+
+	advisees := func() {
+		mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id`).
+			WithArgs("lecturer-1").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("user-1"))
+	}
+
+	advisees()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM students s JOIN users u ON u.id = s.user_id\s+WHERE s.user_id = ANY\(\$1\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`WHERE s.user_id = ANY\(\$1\)`).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"student_id", "user_id", "academic_year", "program_study", "username", "full_name", "email", "sort_key", "row_id",
+		}).AddRow("student-1", "user-1", "2022", "Informatics", "user1", "User One", "user1@gmail.com", "User One", "student-1"))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/lecturer/students", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	// mahasiswa yang bukan bimbingan tidak bisa dibuka
+	advisees()
+	resp, err = app.Test(httptest.NewRequest("GET", "/lecturer/students/user-9", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusForbidden {
+		t.Fatalf("expected 403, got %d", resp.StatusCode)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
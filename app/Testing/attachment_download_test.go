@@ -0,0 +1,135 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/service"
+	"PROJECTUAS_BE/app/signing"
+	"PROJECTUAS_BE/app/storage"
+	"PROJECTUAS_BE/middleware"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newDownloadApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	blobs := storage.NewLocalStore(t.TempDir())
+	content := "0123456789"
+	if err := blobs.Put(context.Background(), "achievements/f1.pdf", strings.NewReader(content), int64(len(content)), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &stubAchievementRepository{byID: map[string]*model.Achievement{
+		"ach-1": {ID: "ach-1", StudentID: "user-1", Attachments: []model.Attachment{
+			{FileName: "sertifikat.pdf", FileType: "application/pdf", StorageKey: "achievements/f1.pdf"},
+		}},
+	}}
+	svc := service.NewAchievementService(repo, nil, blobs, signing.New("secret"), service.DefaultAttachmentPolicy())
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		if !strings.HasPrefix(c.Path(), "/public") {
+			c.Locals("claims", &middleware.Claims{UserID: "admin-1", Role: middleware.RoleAdmin})
+		}
+		return c.Next()
+	})
+	app.Get("/achievements/:id/attachments/:attachmentId", svc.DownloadAttachment)
+	app.Get("/achievements/:id/attachments/:attachmentId/url", svc.GetAttachmentURL)
+	app.Get("/public/attachments/:id/:attachmentId", svc.DownloadSignedAttachment)
+
+	return app
+}
+
+func TestDownloadAttachment_Range(t *testing.T) {
+	app := newDownloadApp(t)
+
+	cases := []struct {
+		rangeHeader  string
+		status       int
+		body         string
+		contentRange string
+	}{
+		{"", fiber.StatusOK, "0123456789", ""},
+		{"bytes=2-5", fiber.StatusPartialContent, "2345", "bytes 2-5/10"},
+		{"bytes=7-", fiber.StatusPartialContent, "789", "bytes 7-9/10"},
+		{"bytes=-3", fiber.StatusPartialContent, "789", "bytes 7-9/10"},
+		{"bytes=8-100", fiber.StatusPartialContent, "89", "bytes 8-9/10"},
+		{"bytes=0-1,4-5", fiber.StatusOK, "0123456789", ""},
+		{"bytes=10-", fiber.StatusRequestedRangeNotSatisfiable, "", "bytes */10"},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "/achievements/ach-1/attachments/f1.pdf", nil)
+		if tc.rangeHeader != "" {
+			req.Header.Set("Range", tc.rangeHeader)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+
+		if resp.StatusCode != tc.status {
+			t.Errorf("%q: expected status %d, got %d", tc.rangeHeader, tc.status, resp.StatusCode)
+			continue
+		}
+		if tc.body != "" && string(body) != tc.body {
+			t.Errorf("%q: expected body %q, got %q", tc.rangeHeader, tc.body, body)
+		}
+		if got := resp.Header.Get("Content-Range"); got != tc.contentRange {
+			t.Errorf("%q: expected Content-Range %q, got %q", tc.rangeHeader, tc.contentRange, got)
+		}
+		if tc.status != fiber.StatusRequestedRangeNotSatisfiable && resp.Header.Get("Content-Type") != "application/pdf" {
+			t.Errorf("%q: unexpected content type %q", tc.rangeHeader, resp.Header.Get("Content-Type"))
+		}
+	}
+}
+
+func TestDownloadAttachment_SignedURL(t *testing.T) {
+	app := newDownloadApp(t)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/achievements/ach-1/attachments/f1.pdf/url", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Data struct {
+			URL string `json:"url"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	link, err := url.Parse(out.Data.URL)
+	if err != nil {
+		t.Fatalf("invalid url %q", out.Data.URL)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", link.Path+"?"+link.RawQuery, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := io.ReadAll(resp.Body); resp.StatusCode != fiber.StatusOK || string(body) != "0123456789" {
+		t.Fatalf("expected signed download to succeed, got %d %q", resp.StatusCode, body)
+	}
+
+	// signature untuk file lain tidak berlaku
+	tampered := strings.Replace(link.Path, "f1.pdf", "f2.pdf", 1) + "?" + link.RawQuery
+	resp, err = app.Test(httptest.NewRequest("GET", tampered, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusForbidden {
+		t.Fatalf("expected 403 for tampered link, got %d", resp.StatusCode)
+	}
+}
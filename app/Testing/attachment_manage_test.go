@@ -0,0 +1,150 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/service"
+	"PROJECTUAS_BE/app/storage"
+	"PROJECTUAS_BE/middleware"
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestDeleteAndReplaceAttachment(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	ctx := context.Background()
+	blobs := storage.NewLocalStore(t.TempDir())
+	for _, key := range []string{"achievements/old.pdf", "achievements/other.pdf"} {
+		if err := blobs.Put(ctx, key, strings.NewReader("x"), 1, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := &stubAchievementRepository{byID: map[string]*model.Achievement{
+		"ach-1": {ID: "ach-1", StudentID: "user-1", Attachments: []model.Attachment{
+			{ID: "att-1", FileName: "lama.pdf", StorageKey: "achievements/old.pdf"},
+			{FileName: "legacy.pdf", StorageKey: "achievements/other.pdf"},
+		}},
+	}}
+	svc := service.NewAchievementService(repo, newLifecycle(db), blobs, nil, service.DefaultAttachmentPolicy())
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("claims", &middleware.Claims{UserID: "user-1", Role: middleware.RoleStudent})
+		return c.Next()
+	})
+	app.Put("/achievements/:id/attachments/:attachmentId", svc.ReplaceAttachment)
+	app.Delete("/achievements/:id/attachments/:attachmentId", svc.DeleteAttachment)
+
+	replace := func(attachmentID string, status string) int {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("file", "baru.png")
+		part.Write(samplePNG(t))
+		form.Close()
+
+		mock.ExpectQuery(`FROM achievement_references`).WithArgs("ach-1").WillReturnRows(referenceRows(status))
+
+		req := httptest.NewRequest("PUT", "/achievements/ach-1/attachments/"+attachmentID, &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	if status := replace("att-1", "verified"); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 for verified achievement, got %d", status)
+	}
+
+	if status := replace("att-1", "draft"); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if len(repo.replaced) != 1 {
+		t.Fatalf("expected 1 replacement, got %d", len(repo.replaced))
+	}
+	replacement := repo.replaced[0]
+	if replacement.ID != "att-1" || replacement.FileType != "image/png" || replacement.StorageKey == "achievements/old.pdf" {
+		t.Fatalf("unexpected replacement: %+v", replacement)
+	}
+	if _, err := blobs.Open(ctx, "achievements/old.pdf"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected old file to be removed, got %v", err)
+	}
+
+	if status := replace("missing", "draft"); status != fiber.StatusNotFound {
+		t.Fatalf("expected 404 for unknown attachment, got %d", status)
+	}
+
+	// lampiran lama tanpa id dialamatkan dengan nama filenya
+	mock.ExpectQuery(`FROM achievement_references`).WithArgs("ach-1").WillReturnRows(referenceRows("rejected"))
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/achievements/ach-1/attachments/other.pdf", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if len(repo.removed) != 1 || repo.removed[0].StorageKey != "achievements/other.pdf" {
+		t.Fatalf("unexpected removed attachments: %+v", repo.removed)
+	}
+	if _, err := blobs.Open(ctx, "achievements/other.pdf"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected file to be removed, got %v", err)
+	}
+}
+
+func TestAttachmentGC_RemovesUnreferencedFiles(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	blobs := storage.NewLocalStore(root)
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, key := range []string{"achievements/used.pdf", "achievements/used.preview.jpg", "achievements/orphan.pdf", "achievements/fresh.pdf", "exports/report.csv"} {
+		if err := blobs.Put(ctx, key, strings.NewReader("x"), 1, ""); err != nil {
+			t.Fatal(err)
+		}
+		if key != "achievements/fresh.pdf" {
+			os.Chtimes(filepath.Join(root, filepath.FromSlash(key)), old, old)
+		}
+	}
+
+	repo := &stubAchievementRepository{byID: map[string]*model.Achievement{
+		"ach-1": {ID: "ach-1", Attachments: []model.Attachment{{StorageKey: "achievements/used.pdf", PreviewKey: "achievements/used.preview.jpg"}}},
+	}}
+
+	report, err := service.NewAttachmentGC(repo, blobs).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Scanned != 4 || report.Removed != 1 || report.Failed != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	for key, exists := range map[string]bool{
+		"achievements/used.pdf":         true,
+		"achievements/used.preview.jpg": true,
+		"achievements/orphan.pdf":       false,
+		"achievements/fresh.pdf":        true,
+		"exports/report.csv":            true,
+	} {
+		_, err := blobs.Open(ctx, key)
+		if exists && err != nil {
+			t.Errorf("%s should be kept, got %v", key, err)
+		}
+		if !exists && !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("%s should be removed, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/service"
+	"PROJECTUAS_BE/app/storage"
+	"PROJECTUAS_BE/middleware"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+)
+
+func samplePNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sampleJPEG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4)), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func samplePDF(body string) []byte {
+	return []byte("%PDF-1.4\n1 0 obj << /Type /Catalog " + body + " >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+}
+
+func TestAttachmentPolicy_Inspect(t *testing.T) {
+	policy := service.AttachmentPolicy{MaxSize: 1 << 20, MaxCount: 3}
+	pngData, jpegData := samplePNG(t), sampleJPEG(t)
+
+	cases := []struct {
+		name        string
+		data        []byte
+		status      int // 0 = diterima
+		contentType string
+	}{
+		{"pdf", samplePDF(""), 0, "application/pdf"},
+		{"png", pngData, 0, "image/png"},
+		{"jpeg", jpegData, 0, "image/jpeg"},
+		{"jpeg with zero padding", append(append([]byte{}, jpegData...), 0, 0), 0, "image/jpeg"},
+		{"encrypted pdf", samplePDF("/Encrypt 2 0 R"), fiber.StatusUnsupportedMediaType, ""},
+		{"pdf javascript", samplePDF("/OpenAction << /S /JavaScript >>"), fiber.StatusUnsupportedMediaType, ""},
+		{"pdf obfuscated javascript", samplePDF("/OpenAction << /S /J#61vaScript >>"), fiber.StatusUnsupportedMediaType, ""},
+		{"pdf with appended data", append(samplePDF(""), []byte("PK\x03\x04zip")...), fiber.StatusUnsupportedMediaType, ""},
+		{"png with appended zip", append(append([]byte{}, pngData...), []byte("PK\x05\x06")...), fiber.StatusUnsupportedMediaType, ""},
+		{"jpeg with appended data", append(append([]byte{}, jpegData...), []byte("<html>")...), fiber.StatusUnsupportedMediaType, ""},
+		{"gif", []byte("GIF89a......"), fiber.StatusUnsupportedMediaType, ""},
+		{"html renamed to pdf", []byte("<html><script>alert(1)</script></html>"), fiber.StatusUnsupportedMediaType, ""},
+		{"too large", append(samplePDF(""), make([]byte, 1<<20)...), fiber.StatusRequestEntityTooLarge, ""},
+		{"empty", []byte{}, fiber.StatusBadRequest, ""},
+	}
+
+	for _, tc := range cases {
+		file, err := policy.Inspect(tc.data)
+
+		if tc.status != 0 {
+			fiberErr, ok := err.(*fiber.Error)
+			if !ok || fiberErr.Code != tc.status {
+				t.Errorf("%s: expected status %d, got %v", tc.name, tc.status, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		sum := sha256.Sum256(tc.data)
+		if file.ContentType != tc.contentType || file.SHA256 != hex.EncodeToString(sum[:]) || file.Size != int64(len(tc.data)) {
+			t.Errorf("%s: unexpected result %+v", tc.name, file)
+		}
+	}
+}
+
+func TestUploadAttachments_UsesSniffedTypeAndRejectsDuplicate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	pngData := samplePNG(t)
+	sum := sha256.Sum256(pngData)
+
+	repo := &stubAchievementRepository{byID: map[string]*model.Achievement{
+		"ach-1": {ID: "ach-1", StudentID: "user-1"},
+		"ach-2": {ID: "ach-2", StudentID: "user-1", Attachments: []model.Attachment{{SHA256: hex.EncodeToString(sum[:])}}},
+	}}
+	blobs := storage.NewLocalStore(t.TempDir())
+	svc := service.NewAchievementService(repo, newLifecycle(db), blobs, nil, service.DefaultAttachmentPolicy())
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("claims", &middleware.Claims{UserID: "user-1", Role: middleware.RoleStudent})
+		return c.Next()
+	})
+	app.Post("/achievements/:id/attachments", svc.UploadAttachments)
+
+	upload := func(id string) int {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="file"; filename="sertifikat.pdf"`)
+		header.Set("Content-Type", "application/pdf") // header client salah, isi file PNG
+		part, _ := form.CreatePart(header)
+		part.Write(pngData)
+		form.Close()
+
+		mock.ExpectQuery(`FROM achievement_references`).WithArgs(id).WillReturnRows(referenceRows("draft"))
+
+		req := httptest.NewRequest("POST", "/achievements/"+id+"/attachments", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	if status := upload("ach-1"); status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if len(repo.added) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(repo.added))
+	}
+
+	added := repo.added[0]
+	if added.FileType != "image/png" || added.SHA256 != hex.EncodeToString(sum[:]) || added.Size != int64(len(pngData)) {
+		t.Fatalf("unexpected attachment metadata: %+v", added)
+	}
+	if added.ID == "" || added.FileUrl != "/api/achievements/ach-1/attachments/"+added.ID {
+		t.Fatalf("expected stable attachment id in url, got %+v", added)
+	}
+
+	obj, err := blobs.Open(context.Background(), added.StorageKey)
+	if err != nil {
+		t.Fatalf("stored file not found: %v", err)
+	}
+	obj.Body.Close()
+
+	if status := upload("ach-2"); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 for duplicate file, got %d", status)
+	}
+}
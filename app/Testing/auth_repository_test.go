@@ -215,3 +215,28 @@ func TestGetProfile_NotFound(t *testing.T) {
 		t.Fatal("expected nil user")
 	}
 }
+
+func TestGetPermissionsByRole_Success(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAuthRepository(db)
+
+	rows := sqlmock.NewRows([]string{"name"}).
+		AddRow("achievement:read").
+		AddRow("achievement:verify")
+
+	mock.ExpectQuery(`FROM permissions p`).
+		WithArgs("dosen").
+		WillReturnRows(rows)
+
+	permissions, err := repo.GetPermissionsByRole("dosen")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(permissions) != 2 || permissions[1] != "achievement:verify" {
+		t.Fatalf("unexpected permissions: %v", permissions)
+	}
+}
@@ -0,0 +1,134 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/service"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func latestReferenceRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "student_id", "mongo_achievement_id", "status",
+		"submitted_at", "verified_at", "verified_by", "rejection_reason", "is_public",
+		"created_at", "updated_at",
+	}).AddRow(
+		"ref-1", "student-1", "mongo-ok", model.StatusSubmitted,
+		nil, nil, nil, nil, false, time.Now(), time.Now(),
+	).AddRow(
+		"ref-2", "student-1", "mongo-gone", model.StatusDraft,
+		nil, nil, nil, nil, false, time.Now(), time.Now(),
+	).AddRow(
+		"ref-3", "student-1", "mongo-deleted", model.StatusDeleted,
+		nil, nil, nil, nil, false, time.Now(), time.Now(),
+	)
+}
+
+func TestConsistencyCheck_ReportOnly(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mongo := &stubAchievementRepository{owners: map[string]string{
+		"mongo-ok":      "user-1",
+		"mongo-deleted": "user-1",
+		"mongo-orphan":  "user-1",
+	}}
+
+	mock.ExpectQuery(`SELECT DISTINCT ON \(mongo_achievement_id\)`).
+		WillReturnRows(latestReferenceRows())
+
+	svc := service.NewConsistencyService(mongo, newLifecycle(db))
+
+	report, err := svc.Run(context.Background(), false, "admin-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Issues) != 3 {
+		t.Fatalf("expected 3 issues, got %d", len(report.Issues))
+	}
+
+	kinds := map[string]string{}
+	for _, issue := range report.Issues {
+		kinds[issue.MongoAchievementID] = issue.Kind
+		if issue.Repaired {
+			t.Fatal("report-only run must not repair")
+		}
+	}
+
+	if kinds["mongo-gone"] != model.IssueMissingDocument ||
+		kinds["mongo-deleted"] != model.IssueDeletedReference ||
+		kinds["mongo-orphan"] != model.IssueMissingReference {
+		t.Fatalf("unexpected issues: %v", kinds)
+	}
+
+	if len(mongo.deleted) != 0 {
+		t.Fatal("report-only run must not delete documents")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConsistencyCheck_Repair(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mongo := &stubAchievementRepository{owners: map[string]string{
+		"mongo-ok":      "user-1",
+		"mongo-deleted": "user-1",
+		"mongo-orphan":  "user-1",
+	}}
+
+	mock.ExpectQuery(`SELECT DISTINCT ON \(mongo_achievement_id\)`).
+		WillReturnRows(latestReferenceRows())
+
+	// reference tanpa dokumen → deleted
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE achievement_references`).
+		WithArgs(model.StatusDeleted, "mongo-gone", model.StatusDraft).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ref-2"))
+	mock.ExpectQuery(`INSERT INTO achievement_status_events`).
+		WillReturnRows(statusEventRows(1))
+	expectOutbox(mock, "status-event:1")
+	mock.ExpectCommit()
+
+	// dokumen tanpa reference → draft baru
+	mock.ExpectQuery(`SELECT id\s+FROM students`).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("student-1"))
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO achievement_references`).
+		WithArgs(sqlmock.AnyArg(), "student-1", "mongo-orphan", model.StatusDraft, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO achievement_status_events`).
+		WithArgs(sqlmock.AnyArg(), "mongo-orphan", sqlmock.AnyArg(), sqlmock.AnyArg(), model.StatusDraft, nil).
+		WillReturnRows(statusEventRows(1))
+	expectOutbox(mock, "status-event:1")
+	mock.ExpectCommit()
+
+	svc := service.NewConsistencyService(mongo, newLifecycle(db))
+
+	report, err := svc.Run(context.Background(), true, "admin-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, issue := range report.Issues {
+		if !issue.Repaired {
+			t.Fatalf("expected %s to be repaired: %s", issue.MongoAchievementID, issue.Error)
+		}
+	}
+
+	if len(mongo.deleted) != 1 || mongo.deleted[0] != "mongo-deleted" {
+		t.Fatalf("expected mongo-deleted to be removed, got %v", mongo.deleted)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,73 @@
+package testing
+
+import (
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
+	"PROJECTUAS_BE/middleware"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+func errorEnvelope(t *testing.T, handlerErr error) (int, response.Envelope, string) {
+	t.Helper()
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(requestid.New())
+	app.Get("/", func(c *fiber.Ctx) error { return handlerErr })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw, _ := io.ReadAll(resp.Body)
+	var out response.Envelope
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("invalid json %s: %v", raw, err)
+	}
+
+	return resp.StatusCode, out, string(raw)
+}
+
+func TestErrorHandler_DomainErrors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{repository.ErrScoringRuleNotFound, fiber.StatusNotFound, response.CodeNotFound},
+		{repository.ErrUserAlreadyExists, fiber.StatusConflict, response.CodeConflict},
+		{repository.ErrRoleNotFound, fiber.StatusBadRequest, response.CodeBadRequest},
+		{repository.Forbidden("not your achievement"), fiber.StatusForbidden, response.CodeForbidden},
+		{fiber.NewError(fiber.StatusUnauthorized, "Unauthorized"), fiber.StatusUnauthorized, response.CodeUnauthorized},
+	}
+
+	for _, tc := range cases {
+		status, out, raw := errorEnvelope(t, tc.err)
+
+		if status != tc.status || out.Error == nil || out.Error.Code != tc.code {
+			t.Errorf("%v: expected %d %s, got %d %s", tc.err, tc.status, tc.code, status, raw)
+		}
+		if out.RequestID == "" {
+			t.Errorf("%v: expected request id in %s", tc.err, raw)
+		}
+	}
+}
+
+func TestErrorHandler_HidesInternalErrors(t *testing.T) {
+	status, out, raw := errorEnvelope(t, errors.New(`pq: relation "users" does not exist`))
+
+	if status != fiber.StatusInternalServerError || out.Error.Code != response.CodeInternal {
+		t.Fatalf("expected 500, got %d %s", status, raw)
+	}
+	if strings.Contains(raw, "relation") {
+		t.Fatalf("internal error text leaked: %s", raw)
+	}
+}
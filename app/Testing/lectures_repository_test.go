@@ -1,128 +1,112 @@
 package testing
 
 import (
+	model "PROJECTUAS_BE/app/Model"
 	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/service"
+	"PROJECTUAS_BE/middleware"
 	"context"
-	"database/sql"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
 )
 
-func TestVerifyAchievement_Success(t *testing.T) {
-	db, mock, _ := sqlmock.New()
-	defer db.Close()
-
-	repo := repository.NewLecturesRepository(db)
-
-	mock.ExpectExec(`UPDATE achievement_references`).
-		WithArgs(
-			"verified",
-			"lecturer-1",
-			nil,
-			"mongo-1",
-		).
-		WillReturnResult(sqlmock.NewResult(0, 1))
-
-	err := repo.Verify(
-		context.Background(),
+func historyRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id",
+		"mongo_achievement_id",
+		"student_id",
+		"user_id",
+		"from_status",
+		"to_status",
+		"actor_id",
+		"full_name",
+		"note",
+		"created_at",
+	}).AddRow(
+		1,
 		"mongo-1",
-		"verified",
-		"lecturer-1",
+		"student-1",
+		"user-1",
+		nil,
+		"draft",
+		"user-1",
+		"Student One",
 		nil,
+		time.Now(),
+	).AddRow(
+		2,
+		"mongo-1",
+		"student-1",
+		"user-1",
+		"draft",
+		"submitted",
+		"user-1",
+		"Student One",
+		nil,
+		time.Now(),
 	)
-
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
 }
 
-func TestVerifyAchievement_NotFound(t *testing.T) {
+func TestGetHistory_Success(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
 
 	repo := repository.NewLecturesRepository(db)
 
-	mock.ExpectExec(`UPDATE achievement_references`).
-		WillReturnResult(sqlmock.NewResult(0, 0))
-
-	err := repo.Verify(
-		context.Background(),
-		"mongo-x",
-		"verified",
-		"lecturer-1",
-		nil,
-	)
+	rows := historyRows()
 
-	if err != sql.ErrNoRows {
-		t.Fatal("expected sql.ErrNoRows")
-	}
-}
+	mock.ExpectQuery(`FROM achievement_status_events`).
+		WithArgs("mongo-1").
+		WillReturnRows(rows)
 
-func TestRejectAchievement_Success(t *testing.T) {
-	db, mock, _ := sqlmock.New()
-	defer db.Close()
+	history, err := repo.GetHistory(context.Background(), "mongo-1")
 
-	repo := repository.NewLecturesRepository(db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	mock.ExpectExec(`UPDATE achievement_references`).
-		WithArgs(
-			"lecturer-1",
-			"Dokumen tidak valid",
-			"mongo-1",
-		).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	if len(history) != 2 {
+		t.Fatalf("expected 2 history entries")
+	}
 
-	err := repo.Reject(
-		context.Background(),
-		"mongo-1",
-		"Dokumen tidak valid",
-		"lecturer-1",
-	)
+	if *history[1].FromStatus != "draft" || history[1].ToStatus != "submitted" {
+		t.Fatalf("unexpected transition: %v -> %s", history[1].FromStatus, history[1].ToStatus)
+	}
 
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	if history[0].StudentUserID != "user-1" {
+		t.Fatalf("expected owner users.id, got %q", history[0].StudentUserID)
 	}
 }
 
-func TestGetHistory_Success(t *testing.T) {
-	db, mock, _ := sqlmock.New()
-	defer db.Close()
-
-	repo := repository.NewLecturesRepository(db)
+func TestGetHistoryHandler_LecturerOnlyAdvisees(t *testing.T) {
+	for advisee, expected := range map[string]int{"user-1": fiber.StatusOK, "user-9": fiber.StatusForbidden} {
+		db, mock, _ := sqlmock.New()
 
-	rows := sqlmock.NewRows([]string{
-		"id",
-		"mongo_achievement_id",
-		"student_id",
-		"status",
-		"submitted_at",
-		"verified_at",
-		"verified_by",
-		"rejection_reason",
-	}).AddRow(
-		"ref-1",
-		"mongo-1",
-		"student-1",
-		"submitted",
-		nil,
-		nil,
-		nil,
-		nil,
-	)
+		svc := service.NewLecturesService(repository.NewLecturesRepository(db), newLifecycle(db), nil, nil)
 
-	mock.ExpectQuery(`FROM achievement_references`).
-		WithArgs("mongo-1").
-		WillReturnRows(rows)
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+		app.Get("/achievements/:id/history", func(c *fiber.Ctx) error {
+			c.Locals("claims", &middleware.Claims{UserID: "lecturer-1", Role: middleware.RoleLecturer})
+			return c.Next()
+		}, svc.GetHistory)
 
-	history, err := repo.GetHistory(context.Background(), "mongo-1")
+		mock.ExpectQuery(`FROM achievement_status_events`).
+			WithArgs("mongo-1").
+			WillReturnRows(historyRows())
+		mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id`).
+			WithArgs("lecturer-1").
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(advisee))
 
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
+		resp, _ := app.Test(httptest.NewRequest("GET", "/achievements/mongo-1/history", nil))
+		if resp.StatusCode != expected {
+			t.Fatalf("advisee %s: expected %d, got %d", advisee, expected, resp.StatusCode)
+		}
 
-	if len(history) != 1 {
-		t.Fatalf("expected 1 history")
+		db.Close()
 	}
 }
 
@@ -139,6 +123,8 @@ func TestGetAllLectures_Success(t *testing.T) {
 		"username",
 		"email",
 		"full_name",
+		"sort_key",
+		"row_id",
 	}).AddRow(
 		"lect-1",
 		"user-1",
@@ -146,19 +132,29 @@ func TestGetAllLectures_Success(t *testing.T) {
 		"lecturer1",
 		"lect@gmail.com",
 		"Lecturer One",
+		"Lecturer One",
+		"lect-1",
 	)
 
-	mock.ExpectQuery(`FROM lecturers`).
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM lecturers l JOIN users u ON u.id = l.user_id WHERE l.department = \$1`).
+		WithArgs("Informatics").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`FROM lecturers .* ORDER BY u.full_name ASC, l.id ASC LIMIT 21 OFFSET 0`).
+		WithArgs("Informatics").
 		WillReturnRows(rows)
 
-	result, err := repo.GetallLectures(context.Background())
+	result, page, err := repo.GetallLectures(
+		context.Background(),
+		model.LecturerFilter{Department: "Informatics"},
+		model.ListQuery{Limit: 20, Page: 1},
+	)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if len(result) != 1 {
-		t.Fatalf("expected 1 lecturer")
+	if len(result) != 1 || page.Total != 1 || page.HasMore {
+		t.Fatalf("expected 1 lecturer on a single page, got %d %+v", len(result), page)
 	}
 }
 
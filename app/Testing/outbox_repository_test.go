@@ -0,0 +1,105 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/service"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func outboxRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"id", "idempotency_key", "mongo_achievement_id", "event_type",
+		"payload", "attempts", "last_error", "created_at",
+	}).AddRow(
+		7, "status-event:3", "mongo-1", model.OutboxStatusSync,
+		[]byte(`{"seq":3,"status":"verified","occurred_at":"2025-01-01T00:00:00Z","verified_by":"lecturer-1"}`),
+		1, nil, time.Now(),
+	)
+}
+
+func TestClaimPendingOutbox_Success(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewOutboxRepository(db)
+
+	mock.ExpectQuery(`UPDATE achievement_outbox`).
+		WithArgs(50, 60, 10).
+		WillReturnRows(outboxRows())
+
+	messages, err := repo.ClaimPending(context.Background(), 50, time.Minute, 10)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(messages) != 1 || messages[0].IdempotencyKey != "status-event:3" {
+		t.Fatalf("unexpected messages: %v", messages)
+	}
+}
+
+func TestOutboxWorker_AppliesAndMarksProcessed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mongo := &stubAchievementRepository{}
+	worker := service.NewOutboxWorker(repository.NewOutboxRepository(db), mongo)
+
+	mock.ExpectQuery(`UPDATE achievement_outbox`).
+		WillReturnRows(outboxRows())
+	mock.ExpectExec(`SET processed_at = NOW\(\)`).
+		WithArgs(int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	processed, err := worker.ProcessBatch(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if processed != 1 {
+		t.Fatalf("expected 1 processed, got %d", processed)
+	}
+
+	if len(mongo.synced) != 1 || mongo.synced[0].Seq != 3 || mongo.synced[0].Status != model.StatusVerified {
+		t.Fatalf("unexpected sync: %v", mongo.synced)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOutboxWorker_FailureSchedulesRetry(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mongo := &stubAchievementRepository{syncErr: errors.New("mongo unavailable")}
+	worker := service.NewOutboxWorker(repository.NewOutboxRepository(db), mongo)
+
+	mock.ExpectQuery(`UPDATE achievement_outbox`).
+		WillReturnRows(outboxRows())
+	mock.ExpectExec(`SET last_error = \$2`).
+		WithArgs(int64(7), "mongo unavailable", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	processed, err := worker.ProcessBatch(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if processed != 0 {
+		t.Fatalf("expected 0 processed, got %d", processed)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,72 @@
+package testing
+
+import (
+	"PROJECTUAS_BE/app/repository"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGrantPermission_Success(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewPermissionRepository(db)
+
+	mock.ExpectExec(`INSERT INTO role_permissions`).
+		WithArgs("role-1", "perm-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Grant(context.Background(), "role-1", "perm-1")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRevokePermission_NotFound(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewPermissionRepository(db)
+
+	mock.ExpectExec(`DELETE FROM role_permissions`).
+		WithArgs("role-1", "perm-x").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Revoke(context.Background(), "role-1", "perm-x")
+
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestGetPermissionsByRoleID_Success(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewPermissionRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "resource", "action"}).
+		AddRow("perm-1", "achievement:verify", "achievement", "verify")
+
+	mock.ExpectQuery(`FROM permissions p`).
+		WithArgs("role-1").
+		WillReturnRows(rows)
+
+	permissions, err := repo.GetPermissionsByRoleID(context.Background(), "role-1")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(permissions) != 1 {
+		t.Fatalf("expected 1 permission")
+	}
+}
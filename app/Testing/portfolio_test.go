@@ -0,0 +1,142 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/service"
+	"PROJECTUAS_BE/app/signing"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSigner_VerifyPurposeAndExpiry(t *testing.T) {
+	signer := signing.New("secret")
+
+	sig := signer.Sign("verify", "ach-1", time.Time{})
+	if !signer.Verify("verify", "ach-1", time.Time{}, sig) {
+		t.Fatal("expected valid signature")
+	}
+	if signer.Verify("download", "ach-1", time.Time{}, sig) {
+		t.Fatal("signature must not be valid for another purpose")
+	}
+	if signer.Verify("verify", "ach-2", time.Time{}, sig) {
+		t.Fatal("signature must not be valid for another subject")
+	}
+	if signing.New("other").Verify("verify", "ach-1", time.Time{}, sig) {
+		t.Fatal("signature must not be valid with another secret")
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	if signer.Verify("download", "file", expired, signer.Sign("download", "file", expired)) {
+		t.Fatal("expired signature must be rejected")
+	}
+}
+
+func TestBuildPortfolio_SkipsForeignDocsAndUsesPostgresPoints(t *testing.T) {
+	points := 50
+	repo := &stubAchievementRepository{docs: []model.Achievement{
+		{ID: "ach-1", StudentID: "user-1", Title: "Juara 1", Points: 10},
+		{ID: "ach-2", StudentID: "user-9", Title: "Bukan miliknya", Points: 99},
+	}}
+	svc := service.NewPortfolioService(nil, repo, signing.New("secret"))
+
+	profile := &model.PortfolioProfile{StudentID: "student-1", UserID: "user-1", Username: "budi"}
+	records := []model.VerificationRecord{
+		{MongoAchievementID: "ach-1", VerifiedAt: time.Now(), VerifierName: "Dr. Sari", Points: &points},
+		{MongoAchievementID: "ach-2", VerifiedAt: time.Now()},
+		{MongoAchievementID: "ach-missing", VerifiedAt: time.Now()},
+	}
+
+	portfolio, err := svc.BuildPortfolio(context.Background(), "https://example.test", profile, records)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(portfolio.Achievements) != 1 {
+		t.Fatalf("expected 1 achievement, got %d", len(portfolio.Achievements))
+	}
+
+	item := portfolio.Achievements[0]
+	if item.Points != 50 || portfolio.TotalPoints != 50 {
+		t.Fatalf("expected points from PostgreSQL, got %d / %d", item.Points, portfolio.TotalPoints)
+	}
+	if item.VerifiedBy != "Dr. Sari" || item.Attachments == nil {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+
+	link, err := url.Parse(item.VerificationURL)
+	if err != nil || link.Path != "/public/verify/ach-1" {
+		t.Fatalf("unexpected verification url %q", item.VerificationURL)
+	}
+	if !signing.New("secret").Verify("achievement-verification", "ach-1", time.Time{}, link.Query().Get("sig")) {
+		t.Fatalf("verification url carries an invalid signature: %q", item.VerificationURL)
+	}
+}
+
+func TestFindVerification_NotVerified(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewPortfolioRepository(db)
+
+	mock.ExpectQuery(`ar.status = 'verified'`).
+		WithArgs("ach-1").
+		WillReturnRows(sqlmock.NewRows([]string{"mongo_achievement_id"}))
+
+	_, err := repo.FindVerification(context.Background(), "ach-1")
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// link verifikasi permanen, tapi berhenti berlaku begitu prestasi tidak dipublikasikan lagi
+func TestVerifyAchievementLink_RequiresPublicAchievement(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	signer := signing.New("secret")
+	repo := &stubAchievementRepository{byID: map[string]*model.Achievement{
+		"ach-1": {ID: "ach-1", StudentID: "user-1", Title: "Juara 1"},
+	}}
+	svc := service.NewPortfolioService(repository.NewPortfolioRepository(db), repo, signer)
+
+	app := fiber.New()
+	app.Get("/public/verify/:id", svc.VerifyAchievementLink)
+
+	link, err := url.Parse(svc.VerificationURL("", "ach-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, public := range []bool{true, false} {
+		mock.ExpectQuery(`ar.status = 'verified'`).
+			WithArgs("ach-1").
+			WillReturnRows(sqlmock.NewRows([]string{
+				"mongo_achievement_id", "full_name", "verified_at", "verifier", "points", "is_public",
+			}).AddRow("ach-1", "Budi", time.Now(), "Dr. Sari", 50, public))
+
+		resp, err := app.Test(httptest.NewRequest("GET", link.String(), nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := fiber.StatusOK
+		if !public {
+			expected = fiber.StatusNotFound
+		}
+		if resp.StatusCode != expected {
+			t.Fatalf("is_public=%v: expected %d, got %d", public, expected, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/preview"
+	"PROJECTUAS_BE/app/service"
+	"PROJECTUAS_BE/app/storage"
+	"PROJECTUAS_BE/middleware"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+)
+
+func encodeImage(t *testing.T, width int, height int, encode func(io.Writer, image.Image) error) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodeJPEG(w io.Writer, img image.Image) error { return jpeg.Encode(w, img, nil) }
+
+// pdfWithImage membungkus satu image XObject di halaman pertama PDF minimal
+func pdfWithImage(dict string, stream []byte) []byte {
+	return pdfWithImageOnPage(1, dict, stream)
+}
+
+// pdfWithImageOnPage: PDF dua halaman, image XObject hanya dipakai di halaman page.
+// Resources halaman pertama diwarisi dari node /Pages.
+func pdfWithImageOnPage(page int, dict string, stream []byte) []byte {
+	resources := [2]string{"<< >>", "<< >>"}
+	resources[page-1] = "<< /XObject << /Im0 6 0 R >> >>"
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	fmt.Fprintf(&buf, "2 0 obj\n<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /Resources %s >>\nendobj\n", resources[0])
+	buf.WriteString("3 0 obj\n<< /Type /Page /Parent 2 0 R /Contents 5 0 R >>\nendobj\n")
+	fmt.Fprintf(&buf, "4 0 obj\n<< /Type /Page /Parent 2 0 R /Resources %s >>\nendobj\n", resources[1])
+	buf.WriteString("5 0 obj\n<< /Length 22 >>\nstream\nq 640 0 0 480 0 0 cm Q\nendstream\nendobj\n")
+	fmt.Fprintf(&buf, "6 0 obj\n<< /Type /XObject /Subtype /Image %s /Length %d >>\nstream\n", dict, len(stream))
+	buf.Write(stream)
+	buf.WriteString("\nendstream\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return buf.Bytes()
+}
+
+func decodedSize(t *testing.T, thumb []byte) (int, int) {
+	t.Helper()
+	img, err := jpeg.Decode(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatalf("preview is not a valid jpeg: %v", err)
+	}
+	return img.Bounds().Dx(), img.Bounds().Dy()
+}
+
+func TestPreviewGenerate(t *testing.T) {
+	var gray bytes.Buffer
+	zw := zlib.NewWriter(&gray)
+	zw.Write(bytes.Repeat([]byte{0x80}, 50*100))
+	zw.Close()
+
+	cases := []struct {
+		name          string
+		contentType   string
+		data          []byte
+		width, height int
+	}{
+		{"large png", "image/png", encodeImage(t, 800, 400, png.Encode), 320, 160},
+		{"small png not upscaled", "image/png", encodeImage(t, 40, 30, png.Encode), 40, 30},
+		{"portrait jpeg", "image/jpeg", encodeImage(t, 600, 1200, encodeJPEG), 160, 320},
+		{"pdf with scanned page", "application/pdf", pdfWithImage(
+			"/Width 640 /Height 480 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode",
+			encodeImage(t, 640, 480, encodeJPEG)), 320, 240},
+		{"pdf with gray flate image", "application/pdf", pdfWithImage(
+			"/Width 50 /Height 100 /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode",
+			gray.Bytes()), 50, 100},
+	}
+
+	for _, tc := range cases {
+		thumb, err := preview.Generate(tc.contentType, tc.data)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+			continue
+		}
+		if w, h := decodedSize(t, thumb); w != tc.width || h != tc.height {
+			t.Errorf("%s: expected %dx%d, got %dx%d", tc.name, tc.width, tc.height, w, h)
+		}
+	}
+
+	unavailable := map[string][]byte{
+		"text only pdf": []byte("%PDF-1.4\n1 0 obj\n<< /Length 10 >>\nstream\nBT ET\nendstream\nendobj\n%%EOF\n"),
+		"corrupt jpeg":  pdfWithImage("/Width 10 /Height 10 /Filter /DCTDecode", []byte("not a jpeg")),
+		"image only on page 2": pdfWithImageOnPage(2,
+			"/Width 640 /Height 480 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode",
+			encodeImage(t, 640, 480, encodeJPEG)),
+		"predictor stream": pdfWithImage("/Width 50 /Height 100 /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode /DecodeParms << /Predictor 15 >>", gray.Bytes()),
+	}
+	for name, data := range unavailable {
+		if _, err := preview.Generate("application/pdf", data); !errors.Is(err, preview.ErrUnavailable) {
+			t.Errorf("%s: expected ErrUnavailable, got %v", name, err)
+		}
+	}
+
+	// latar transparan menjadi putih, bukan hitam
+	transparent := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	var buf bytes.Buffer
+	png.Encode(&buf, transparent)
+	thumb, err := preview.Generate("image/png", buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, _ := jpeg.Decode(bytes.NewReader(thumb))
+	if r, _, _, _ := img.At(5, 5).RGBA(); r < 0xf000 {
+		t.Fatalf("expected white background, got %v", color.RGBAModel.Convert(img.At(5, 5)))
+	}
+}
+
+func TestUploadAttachments_GeneratesPreview(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := &stubAchievementRepository{byID: map[string]*model.Achievement{
+		"ach-1": {ID: "ach-1", StudentID: "user-1"},
+	}}
+	blobs := storage.NewLocalStore(t.TempDir())
+	svc := service.NewAchievementService(repo, newLifecycle(db), blobs, nil, service.DefaultAttachmentPolicy())
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("claims", &middleware.Claims{UserID: "user-1", Role: middleware.RoleStudent})
+		return c.Next()
+	})
+	app.Post("/achievements/:id/attachments", svc.UploadAttachments)
+	app.Get("/achievements/:id/attachments/:attachmentId/preview", svc.GetAttachmentPreview)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "sertifikat.png")
+	part.Write(encodeImage(t, 1000, 700, png.Encode))
+	form.Close()
+
+	mock.ExpectQuery(`FROM achievement_references`).WithArgs("ach-1").WillReturnRows(referenceRows("draft"))
+
+	req := httptest.NewRequest("POST", "/achievements/ach-1/attachments", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusCreated || len(repo.added) != 1 {
+		t.Fatalf("expected upload to succeed, got %d", resp.StatusCode)
+	}
+
+	added := repo.added[0]
+	if !added.HasPreview || added.PreviewKey == "" || added.PreviewKey == added.StorageKey {
+		t.Fatalf("expected preview metadata, got %+v", added)
+	}
+
+	repo.byID["ach-1"].Attachments = []model.Attachment{added}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/achievements/ach-1/attachments/"+added.ID+"/preview", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumb, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK || resp.Header.Get("Content-Type") != "image/jpeg" {
+		t.Fatalf("expected jpeg preview, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if w, h := decodedSize(t, thumb); w != 320 || h != 224 {
+		t.Fatalf("unexpected preview size %dx%d", w, h)
+	}
+
+	// lampiran tanpa preview → 404
+	repo.byID["ach-1"].Attachments = []model.Attachment{{ID: "att-2", StorageKey: "achievements/x.pdf"}}
+	resp, _ = app.Test(httptest.NewRequest("GET", "/achievements/ach-1/attachments/att-2/preview", nil))
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("expected 404 without preview, got %d", resp.StatusCode)
+	}
+
+	if _, err := blobs.Open(context.Background(), added.PreviewKey); err != nil {
+		t.Fatalf("preview file not stored: %v", err)
+	}
+}
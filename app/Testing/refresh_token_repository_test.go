@@ -0,0 +1,100 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRotateRefreshToken_Success(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewRefreshTokenRepository(db)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "family_id", "expires_at", "used_at", "revoked_at",
+	}).AddRow(
+		"token-1",
+		"user-1",
+		"family-1",
+		time.Now().Add(time.Hour),
+		nil,
+		nil,
+	)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM refresh_tokens`).
+		WithArgs("old-hash").
+		WillReturnRows(rows)
+	mock.ExpectExec(`UPDATE refresh_tokens`).
+		WithArgs("token-2", "token-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO refresh_tokens`).
+		WithArgs("token-2", "user-1", "family-1", "new-hash", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	next := &model.RefreshToken{
+		ID:        "token-2",
+		TokenHash: "new-hash",
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	}
+
+	old, err := repo.Rotate(context.Background(), "old-hash", next)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if old.UserID != "user-1" || next.FamilyID != "family-1" {
+		t.Fatal("expected new token to inherit user and family")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRotateRefreshToken_ReuseRevokesFamily(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewRefreshTokenRepository(db)
+
+	usedAt := time.Now().Add(-time.Minute)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "family_id", "expires_at", "used_at", "revoked_at",
+	}).AddRow(
+		"token-1",
+		"user-1",
+		"family-1",
+		time.Now().Add(time.Hour),
+		usedAt,
+		nil,
+	)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM refresh_tokens`).
+		WithArgs("old-hash").
+		WillReturnRows(rows)
+	mock.ExpectExec(`UPDATE refresh_tokens`).
+		WithArgs("family-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	_, err := repo.Rotate(context.Background(), "old-hash", &model.RefreshToken{ID: "token-2"})
+
+	if err != repository.ErrRefreshTokenReused {
+		t.Fatalf("expected ErrRefreshTokenReused, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,291 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/service"
+	"PROJECTUAS_BE/middleware"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+)
+
+func expectStudentReportQueries(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`FROM achievement_references\s+WHERE student_id = \$1\s+AND status NOT IN \('draft', 'deleted'\)`).
+		WithArgs("student-1").
+		WillReturnRows(sqlmock.NewRows([]string{"total", "submitted", "verified", "rejected"}).
+			AddRow(3, 1, 1, 1))
+
+	now := time.Now()
+	// daftar memakai filter yang sama dengan hitungan: draft tidak ikut
+	mock.ExpectQuery(`FROM achievement_references ar\s+WHERE ar.student_id = \$1\s+AND ar.status NOT IN \('draft', 'deleted'\)`).
+		WithArgs("student-1").
+		WillReturnRows(sqlmock.NewRows([]string{"mongo_achievement_id", "status", "submitted_at", "verified_at"}).
+			AddRow("mongo-1", model.StatusVerified, now, now).
+			AddRow("mongo-2", model.StatusSubmitted, now, nil).
+			AddRow("mongo-gone", model.StatusRejected, now, now))
+}
+
+func TestGetStudentReport_NoSQLJoin(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewReportRepository(db)
+
+	expectStudentReportQueries(mock)
+
+	report, err := repo.GetStudentReport(context.Background(), "student-1")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Total != 3 || len(report.Achievements) != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildStudentReport_MergesMongoData(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expectStudentReportQueries(mock)
+
+	mongo := &stubAchievementRepository{docs: []model.Achievement{
+		{
+			ID:              "mongo-1",
+			Title:           "Juara 1 Hackathon",
+			AchievementType: "competition",
+			Points:          50,
+			Details:         model.NewAchievementDetails(&model.CompetitionDetails{CompetitionLevel: "national", Rank: 1}),
+		},
+		{
+			ID:              "mongo-2",
+			Title:           "Paper",
+			AchievementType: "publication",
+			Points:          30,
+		},
+	}}
+
+	svc := service.NewReportService(repository.NewReportRepository(db), mongo)
+
+	report, err := svc.BuildStudentReport(context.Background(), "student-1")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := report.Achievements[0]
+	if first.Title != "Juara 1 Hackathon" || first.Details == nil || first.Details.Competition().Rank != 1 {
+		t.Fatalf("expected mongo data merged, got %+v", first)
+	}
+
+	if !report.Achievements[2].Missing {
+		t.Fatal("expected missing document to be flagged")
+	}
+
+	// hanya prestasi verified yang dihitung
+	if report.TotalPoints != 50 {
+		t.Fatalf("expected 50 total points, got %d", report.TotalPoints)
+	}
+}
+
+func newStudentReportApp(t *testing.T, claims *middleware.Claims) (*fiber.App, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+	t.Cleanup(func() { db.Close() })
+
+	svc := service.NewReportService(repository.NewReportRepository(db), &stubAchievementRepository{})
+
+	app := fiber.New()
+	app.Get("/reports/student/:id", func(c *fiber.Ctx) error {
+		c.Locals("claims", claims)
+		return c.Next()
+	}, svc.GetStudentReport)
+
+	return app, mock
+}
+
+func TestGetStudentReport_StudentCanFetchOwnReport(t *testing.T) {
+	app, mock := newStudentReportApp(t, &middleware.Claims{UserID: "user-1", Role: middleware.RoleStudent})
+
+	mock.ExpectQuery(`FROM students s\s+WHERE s.id = \$1\s+AND s.user_id = \$2`).
+		WithArgs("student-1", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	expectStudentReportQueries(mock)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/reports/student/student-1", nil))
+
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetStudentReport_AdvisorResolvedThroughLecturerUser(t *testing.T) {
+	app, mock := newStudentReportApp(t, &middleware.Claims{UserID: "lecturer-user-1", Role: middleware.RoleLecturer})
+
+	mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id\s+WHERE s.id = \$1\s+AND l.user_id = \$2`).
+		WithArgs("student-2", "lecturer-user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/reports/student/student-2", nil))
+
+	if resp.StatusCode != fiber.StatusForbidden {
+		t.Fatalf("expected 403, got %d", resp.StatusCode)
+	}
+}
+
+func TestGetReferenceStatuses_LecturerFilter(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewReportRepository(db)
+
+	mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id[\s\S]+ar.status NOT IN \('draft', 'deleted'\)`).
+		WithArgs("lecturer-user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"mongo_achievement_id", "status"}).
+			AddRow("mongo-1", model.StatusVerified).
+			AddRow("mongo-2", model.StatusSubmitted))
+
+	lecturerID := "lecturer-user-1"
+	statuses, err := repo.GetReferenceStatuses(context.Background(), repository.StatisticsFilter{LecturerID: &lecturerID})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(statuses) != 2 || statuses["mongo-1"] != model.StatusVerified {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+}
+
+func TestBuildStatistics_PassesStatusesToMongo(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`WHERE s.user_id = \$1\s+AND ar.status NOT IN \('draft', 'deleted'\)`).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"mongo_achievement_id", "status"}).AddRow("mongo-1", model.StatusRejected))
+
+	mongo := &stubAchievementRepository{}
+	svc := service.NewReportService(repository.NewReportRepository(db), mongo)
+
+	studentID := "user-1"
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	stats, err := svc.BuildStatistics(context.Background(), repository.StatisticsFilter{StudentID: &studentID}, model.StatisticsQuery{
+		Period: model.PeriodSemester,
+		From:   &from,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Total != 1 || mongo.query.Statuses["mongo-1"] != model.StatusRejected || mongo.query.From != &from {
+		t.Fatalf("unexpected aggregation query: %+v", mongo.query)
+	}
+}
+
+func TestBuildStatistics_AdminCountsAll(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM achievement_references ar\s+WHERE ar.status NOT IN \('draft', 'deleted'\)`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"mongo_achievement_id", "status"}).
+			AddRow("mongo-1", model.StatusVerified).
+			AddRow("mongo-2", model.StatusSubmitted))
+
+	mongo := &stubAchievementRepository{}
+	svc := service.NewReportService(repository.NewReportRepository(db), mongo)
+
+	stats, err := svc.BuildStatistics(context.Background(), repository.StatisticsFilter{}, model.StatisticsQuery{Period: model.PeriodMonth})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Total != 2 {
+		t.Fatalf("expected all references for admin, got %+v", mongo.query)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func leaderboardService(t *testing.T) *service.ReportService {
+	db, mock, _ := sqlmock.New()
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectQuery(`WHERE ar.status = 'verified'`).
+		WithArgs("Informatics", "").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"mongo_achievement_id", "id", "user_id", "full_name", "program_study", "academic_year",
+		}).
+			AddRow("mongo-1", "student-1", "user-1", "Andi", "Informatics", "2022").
+			AddRow("mongo-2", "student-2", "user-2", "Budi", "Informatics", "2022").
+			AddRow("mongo-3", "student-3", "user-3", "Citra", "Informatics", "2023"))
+
+	mongo := &stubAchievementRepository{points: []model.StudentPoints{
+		{UserID: "user-1", Points: 40, Achievements: 2},
+		{UserID: "user-2", Points: 70, Achievements: 3},
+		{UserID: "user-3", Points: 40, Achievements: 1},
+	}}
+
+	return service.NewReportService(repository.NewReportRepository(db), mongo)
+}
+
+func TestBuildLeaderboard_TiesShareRank(t *testing.T) {
+	svc := leaderboardService(t)
+
+	board, err := svc.BuildLeaderboard(context.Background(), model.LeaderboardFilter{
+		ProgramStudy: "Informatics",
+		Page:         1,
+		Limit:        20,
+	}, "admin-1", false)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ranks := []int{board.Data[0].Rank, board.Data[1].Rank, board.Data[2].Rank}
+	if board.Data[0].FullName != "Budi" || ranks[0] != 1 || ranks[1] != 2 || ranks[2] != 2 {
+		t.Fatalf("unexpected ranking: %+v", board.Data)
+	}
+}
+
+func TestBuildLeaderboard_StudentSeesAnonymizedRanks(t *testing.T) {
+	svc := leaderboardService(t)
+
+	board, err := svc.BuildLeaderboard(context.Background(), model.LeaderboardFilter{
+		ProgramStudy: "Informatics",
+		Page:         2,
+		Limit:        2,
+	}, "user-1", true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if board.Total != 3 || len(board.Data) != 1 {
+		t.Fatalf("unexpected page: %+v", board)
+	}
+
+	if !board.Data[0].Anonymized || board.Data[0].FullName != "" {
+		t.Fatalf("expected other students to be anonymized: %+v", board.Data[0])
+	}
+
+	if board.Me == nil || board.Me.FullName != "Andi" || board.Me.Rank != 2 {
+		t.Fatalf("expected own rank to be visible: %+v", board.Me)
+	}
+}
@@ -0,0 +1,56 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/response"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func pageMeta(t *testing.T, target string, info model.PageInfo) model.PageInfo {
+	t.Helper()
+
+	app := fiber.New()
+	app.Get("/api/students", func(c *fiber.Ctx) error {
+		return response.Page(c, []string{"a"}, info)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw, _ := io.ReadAll(resp.Body)
+	var out struct {
+		Meta model.PageInfo `json:"meta"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("invalid json %s: %v", raw, err)
+	}
+	return out.Meta
+}
+
+func TestPage_NextLink(t *testing.T) {
+	meta := pageMeta(t, "/api/students?program_study=TI&page=2&limit=10",
+		model.PageInfo{Total: 35, Limit: 10, Page: 2, HasMore: true, NextCursor: "abc"})
+
+	if meta.Next != "/api/students?limit=10&page=3&program_study=TI" {
+		t.Fatalf("unexpected offset link: %q", meta.Next)
+	}
+
+	meta = pageMeta(t, "/api/students?cursor=old&limit=10",
+		model.PageInfo{Total: 35, Limit: 10, HasMore: true, NextCursor: "abc"})
+
+	if meta.Next != "/api/students?cursor=abc&limit=10" {
+		t.Fatalf("unexpected cursor link: %q", meta.Next)
+	}
+
+	meta = pageMeta(t, "/api/students", model.PageInfo{Total: 1, Limit: 20, Page: 1})
+	if meta.Next != "" {
+		t.Fatalf("expected no next link on last page, got %q", meta.Next)
+	}
+}
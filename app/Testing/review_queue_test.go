@@ -0,0 +1,294 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/service"
+	"PROJECTUAS_BE/middleware"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+)
+
+var reviewQueueColumns = []string{
+	"id", "mongo_achievement_id", "student_id", "user_id", "full_name", "username",
+	"submitted_at", "claimed_by", "claimed_at",
+}
+
+func newReviewQueueApp(db *sql.DB, achievements *stubAchievementRepository) *fiber.App {
+	refs := repository.NewAchievementReferenceRepository(db)
+	scoring := service.NewScoringService(repository.NewScoringRuleRepository(db), achievements, refs)
+	svc := service.NewLecturesService(repository.NewLecturesRepository(db), newLifecycle(db), achievements, scoring)
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("claims", &middleware.Claims{UserID: "lecturer-user-1", Role: middleware.RoleLecturer})
+		return c.Next()
+	})
+	app.Get("/lecturer/queue", svc.GetReviewQueue)
+	app.Post("/lecturer/queue/decisions", svc.DecideBatch)
+	app.Post("/lecturer/queue/:id/claim", svc.ClaimReview)
+	app.Post("/lecturer/achievements/:id/reject", svc.RejectAchievement)
+
+	return app
+}
+
+func TestApplyTransitions_RollsBackOnlyFailedItem(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAchievementReferenceRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT transition`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`UPDATE achievement_references`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ref-1"))
+	mock.ExpectQuery(`INSERT INTO achievement_status_events`).WillReturnRows(statusEventRows(1))
+	expectOutbox(mock, "status-event:1")
+	mock.ExpectExec(`RELEASE SAVEPOINT transition`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT transition`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`UPDATE achievement_references`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id`).
+		WithArgs("mongo-2", "lecturer-1", sqlmock.AnyArg()).
+		WillReturnRows(transitionCheckRows(true, false))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT transition`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	results, err := repo.ApplyTransitions(context.Background(), []model.StatusTransition{
+		{MongoAchievementID: "mongo-1", From: model.StatusSubmitted, To: model.StatusVerified, ActorID: "lecturer-1"},
+		{MongoAchievementID: "mongo-2", From: model.StatusSubmitted, To: model.StatusRejected, ActorID: "lecturer-1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0] != nil || !errors.Is(results[1], repository.ErrStatusChanged) {
+		t.Fatalf("unexpected results: %v", results)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecideBatch_PerItemResults(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	achievements := &stubAchievementRepository{docs: []model.Achievement{
+		{ID: "mongo-1", AchievementType: "competition"},
+		{ID: "mongo-2", AchievementType: "competition"},
+		{ID: "mongo-4", AchievementType: "competition"},
+	}}
+	app := newReviewQueueApp(db, achievements)
+
+	submitted := time.Now().Add(-time.Hour)
+	claimedAt := time.Now().Add(-5 * time.Minute)
+	mock.ExpectQuery(`FROM achievement_references ar`).
+		WillReturnRows(sqlmock.NewRows(reviewQueueColumns).
+			AddRow("ref-1", "mongo-1", "student-1", "user-1", "Budi", "budi", submitted, nil, nil).
+			AddRow("ref-2", "mongo-2", "student-1", "user-1", "Budi", "budi", submitted, nil, nil).
+			AddRow("ref-4", "mongo-4", "student-2", "user-2", "Sari", "sari", submitted, nil, nil).
+			AddRow("ref-5", "mongo-5", "student-2", "user-2", "Sari", "sari", submitted, "lecturer-user-2", claimedAt))
+	for range 2 {
+		mock.ExpectQuery(`FROM scoring_rules`).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"id", "achievement_type", "competition_level", "rank", "medal_type", "points", "created_at", "updated_at",
+			}).AddRow("rule-1", "competition", nil, nil, nil, 25, time.Now(), time.Now()))
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT transition`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`UPDATE achievement_references`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ref-1"))
+	mock.ExpectQuery(`INSERT INTO achievement_status_events`).WillReturnRows(statusEventRows(1))
+	expectOutbox(mock, "status-event:1")
+	mock.ExpectExec(`RELEASE SAVEPOINT transition`).WillReturnResult(sqlmock.NewResult(0, 0))
+	// mongo-2 sudah diputuskan request lain
+	mock.ExpectExec(`SAVEPOINT transition`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`UPDATE achievement_references`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id`).
+		WithArgs("mongo-2", "lecturer-user-1", sqlmock.AnyArg()).
+		WillReturnRows(transitionCheckRows(true, false))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT transition`).WillReturnResult(sqlmock.NewResult(0, 0))
+	// dosen wali mahasiswa mongo-4 diganti setelah antrean dibaca
+	mock.ExpectExec(`SAVEPOINT transition`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`UPDATE achievement_references`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id`).
+		WithArgs("mongo-4", "lecturer-user-1", sqlmock.AnyArg()).
+		WillReturnRows(transitionCheckRows(false, false))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT transition`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	body := `{"items": [
+		{"id": "mongo-1", "status": "verified"},
+		{"id": "mongo-2", "status": "rejected", "rejection_reason": "Sertifikat tidak terbaca"},
+		{"id": "mongo-3", "status": "verified"},
+		{"id": "mongo-4", "status": "verified"},
+		{"id": "mongo-5", "status": "verified"},
+		{"id": "mongo-1", "status": "rejected", "rejection_reason": "duplikat"}
+	]}`
+	req := httptest.NewRequest("POST", "/lecturer/queue/decisions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Data struct {
+			Succeeded int                        `json:"succeeded"`
+			Failed    int                        `json:"failed"`
+			Results   []model.BulkDecisionResult `json:"results"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Data.Succeeded != 1 || out.Data.Failed != 5 {
+		t.Fatalf("unexpected summary: %+v", out.Data)
+	}
+
+	expected := []struct {
+		success bool
+		code    string
+	}{
+		{true, ""},
+		{false, "CONFLICT"},
+		{false, "NOT_FOUND"},
+		{false, "FORBIDDEN"},
+		{false, "CONFLICT"},
+		{false, "BAD_REQUEST"},
+	}
+	for i, e := range expected {
+		r := out.Data.Results[i]
+		if r.Success != e.success || r.Code != e.code {
+			t.Errorf("item %d: expected success=%v code=%q, got %+v", i, e.success, e.code, r)
+		}
+	}
+	if first := out.Data.Results[0]; first.Status != model.StatusVerified || first.Points == nil || *first.Points != 25 {
+		t.Errorf("unexpected verified result: %+v", first)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetReviewQueue_SLAAndContent(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	achievements := &stubAchievementRepository{docs: []model.Achievement{{ID: "mongo-1", Title: "Juara 1 Hackathon"}}}
+	app := newReviewQueueApp(db, achievements)
+
+	old := time.Now().Add(-100 * time.Hour)
+	recent := time.Now().Add(-2 * time.Hour)
+	staleClaim := time.Now().Add(-2 * time.Hour)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM achievement_references ar`).
+		WithArgs("lecturer-user-1", model.StatusSubmitted).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`ORDER BY ar.submitted_at ASC`).
+		WillReturnRows(sqlmock.NewRows(append(reviewQueueColumns, "sort", "row_id")).
+			AddRow("ref-1", "mongo-1", "student-1", "user-1", "Budi", "budi", old, nil, nil, old.String(), "ref-1").
+			AddRow("ref-2", "mongo-2", "student-1", "user-1", "Budi", "budi", recent, "lecturer-user-2", staleClaim, recent.String(), "ref-2"))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/lecturer/queue", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Data []model.ReviewQueueItem `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Data) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(out.Data))
+	}
+
+	first, second := out.Data[0], out.Data[1]
+	if !first.Overdue || first.WaitingHours != 100 || first.Achievement == nil || first.Achievement.Title != "Juara 1 Hackathon" {
+		t.Errorf("unexpected first item: %+v", first)
+	}
+	if second.Overdue || second.Achievement != nil {
+		t.Errorf("unexpected second item: %+v", second)
+	}
+	if second.ClaimedBy != nil || second.ClaimedUntil != nil {
+		t.Errorf("expected expired claim to be hidden, got %+v", second)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRejectAchievement_OnlyAdvisor(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	app := newReviewQueueApp(db, &stubAchievementRepository{})
+
+	mock.ExpectQuery(`FROM achievement_references`).
+		WithArgs("mongo-1").
+		WillReturnRows(referenceRows(model.StatusSubmitted))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE achievement_references ar`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id`).
+		WithArgs("mongo-1", "lecturer-user-1", sqlmock.AnyArg()).
+		WillReturnRows(transitionCheckRows(false, false))
+	mock.ExpectRollback()
+
+	req := httptest.NewRequest("POST", "/lecturer/achievements/mongo-1/reject", strings.NewReader(`{"reason": "Sertifikat tidak terbaca"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusForbidden {
+		t.Fatalf("expected 403, got %d", resp.StatusCode)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClaimReview_ClaimedByAnotherLecturer(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	app := newReviewQueueApp(db, &stubAchievementRepository{})
+
+	mock.ExpectQuery(`UPDATE achievement_references ar[\s\S]+JOIN lecturers l ON l.id = s.advisor_id`).
+		WithArgs("mongo-1", "lecturer-user-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"claimed_at"}))
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/lecturer/queue/mongo-1/claim", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusConflict {
+		t.Fatalf("expected 409, got %d", resp.StatusCode)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+package testing
+
+import (
+	"PROJECTUAS_BE/app/repository"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRevokeToken_Success(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewRevocationRepository(db)
+
+	exp := time.Now().Add(time.Hour)
+
+	mock.ExpectExec(`INSERT INTO revoked_tokens`).
+		WithArgs("jti-1", exp).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Revoke(context.Background(), "jti-1", exp)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsRevoked_True(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewRevocationRepository(db)
+
+	rows := sqlmock.NewRows([]string{"exists"}).AddRow(true)
+
+	mock.ExpectQuery(`FROM revoked_tokens`).
+		WithArgs("jti-1").
+		WillReturnRows(rows)
+
+	revoked, err := repo.IsRevoked(context.Background(), "jti-1")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !revoked {
+		t.Fatal("expected token to be revoked")
+	}
+}
+
+func TestPurgeExpired_Success(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewRevocationRepository(db)
+
+	mock.ExpectExec(`DELETE FROM revoked_tokens`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	purged, err := repo.PurgeExpired(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if purged != 3 {
+		t.Fatalf("expected 3 purged, got %d", purged)
+	}
+}
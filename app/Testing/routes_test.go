@@ -0,0 +1,104 @@
+package testing
+
+import (
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/service"
+	"PROJECTUAS_BE/app/signing"
+	"PROJECTUAS_BE/middleware"
+	"PROJECTUAS_BE/routes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+)
+
+func bearer(t *testing.T, role string) string {
+	t.Helper()
+	token, err := middleware.GenerateToken("user-1", "user", "user@example.com", role, []string{"achievement:verify"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
+// guard group /lecturer tidak boleh ikut berlaku untuk /lecturers milik semua role
+func TestRoutes_LecturerGuardDoesNotLeakToLecturers(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret")
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	lectures := service.NewLecturesService(repository.NewLecturesRepository(db), nil, nil, nil)
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	routes.SetupRoutes(app, nil, nil, nil, lectures, nil, nil, nil, nil, nil, nil)
+
+	for _, role := range []string{middleware.RoleAdmin, middleware.RoleStudent, middleware.RoleLecturer} {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM lecturers`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(`FROM lecturers`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "department", "username", "email", "full_name", "sort", "row_id"}))
+
+		req := httptest.NewRequest("GET", "/api/lecturers", nil)
+		req.Header.Set("Authorization", bearer(t, role))
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != fiber.StatusOK {
+			t.Errorf("%s: expected 200 on /api/lecturers, got %d", role, resp.StatusCode)
+		}
+	}
+
+	// guard role /lecturer tetap berlaku untuk endpoint-nya sendiri
+	req := httptest.NewRequest("GET", "/api/lecturer/queue", nil)
+	req.Header.Set("Authorization", bearer(t, middleware.RoleStudent))
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusForbidden {
+		t.Fatalf("expected 403 for student on /api/lecturer/queue, got %d", resp.StatusCode)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// portfolio publik ada di /public/..., bukan di bawah /api
+func TestRoutes_PublicGroupOnAppRoot(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	portfolio := service.NewPortfolioService(repository.NewPortfolioRepository(db), &stubAchievementRepository{}, signing.New("secret"))
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	routes.SetupRoutes(app, nil, nil, nil, nil, nil, nil, nil, nil, nil, portfolio)
+
+	mock.ExpectQuery(`FROM users u\s+JOIN students s`).
+		WithArgs("budi").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "username", "full_name", "program_study", "academic_year"}).
+			AddRow("student-1", "user-1", "budi", "Budi", "Informatics", "2022"))
+	mock.ExpectQuery(`AND ar.is_public`).
+		WithArgs("student-1").
+		WillReturnRows(sqlmock.NewRows([]string{"mongo_achievement_id", "verified_at", "full_name", "points", "is_public"}))
+
+	for path, expected := range map[string]int{
+		"/public/portfolio/budi":     fiber.StatusOK,
+		"/api/public/portfolio/budi": fiber.StatusNotFound,
+	} {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != expected {
+			t.Errorf("%s: expected %d, got %d", path, expected, resp.StatusCode)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,139 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/service"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func strPtr(s string) *string { return &s }
+func intPtr(i int) *int       { return &i }
+
+func competitionDetails(d model.CompetitionDetails) model.AchievementDetails {
+	return model.NewAchievementDetails(&d)
+}
+
+func TestCreateScoringRule_Duplicate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewScoringRuleRepository(db)
+
+	mock.ExpectQuery(`INSERT INTO scoring_rules`).
+		WithArgs("competition", strPtr("national"), nil, nil, 50).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	_, err := repo.Create(context.Background(), &model.ScoringRuleRequest{
+		AchievementType:  "competition",
+		CompetitionLevel: strPtr("national"),
+		Points:           50,
+	})
+
+	if err != repository.ErrScoringRuleExists {
+		t.Fatalf("expected ErrScoringRuleExists, got %v", err)
+	}
+}
+
+func TestDeleteScoringRule_NotFound(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewScoringRuleRepository(db)
+
+	mock.ExpectExec(`DELETE FROM scoring_rules`).
+		WithArgs("rule-x").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.Delete(context.Background(), "rule-x"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestScoreAchievement_MostSpecificRuleWins(t *testing.T) {
+	rules := []model.ScoringRule{
+		{AchievementType: "competition", Points: 10},
+		{AchievementType: "competition", CompetitionLevel: strPtr("national"), Points: 40},
+		{AchievementType: "competition", CompetitionLevel: strPtr("national"), Rank: intPtr(1), Points: 80},
+		{AchievementType: "publication", Points: 30},
+	}
+
+	first := &model.Achievement{
+		AchievementType: "competition",
+		Details:         competitionDetails(model.CompetitionDetails{CompetitionLevel: "National", Rank: 1}),
+	}
+	third := &model.Achievement{
+		AchievementType: "competition",
+		Details:         competitionDetails(model.CompetitionDetails{CompetitionLevel: "national", Rank: 3}),
+	}
+	regional := &model.Achievement{
+		AchievementType: "competition",
+		Details:         competitionDetails(model.CompetitionDetails{CompetitionLevel: "regional", Rank: 1}),
+	}
+	other := &model.Achievement{AchievementType: "certification"}
+
+	cases := map[*model.Achievement]int{first: 80, third: 40, regional: 10, other: 0}
+	for achievement, expected := range cases {
+		if got := service.ScoreAchievement(rules, achievement); got != expected {
+			t.Fatalf("expected %d points for %+v, got %d", expected, achievement.Details.Competition(), got)
+		}
+	}
+}
+
+func TestRecalculatePoints_UpdatesChangedOnly(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mongo := &stubAchievementRepository{docs: []model.Achievement{
+		{ID: "mongo-1", AchievementType: "competition"},
+	}}
+
+	svc := service.NewScoringService(
+		repository.NewScoringRuleRepository(db),
+		mongo,
+		repository.NewAchievementReferenceRepository(db),
+	)
+
+	mock.ExpectQuery(`FROM scoring_rules`).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "achievement_type", "competition_level", "rank", "medal_type", "points", "created_at", "updated_at",
+		}).AddRow("rule-1", "competition", nil, nil, nil, 25, time.Now(), time.Now()))
+
+	mock.ExpectQuery(`SELECT DISTINCT ON \(mongo_achievement_id\)`).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "student_id", "mongo_achievement_id", "status",
+			"submitted_at", "verified_at", "verified_by", "rejection_reason", "is_public",
+			"created_at", "updated_at",
+		}).
+			AddRow("ref-1", "student-1", "mongo-1", model.StatusVerified, nil, nil, nil, nil, false, time.Now(), time.Now()).
+			AddRow("ref-2", "student-1", "mongo-2", model.StatusVerified, nil, nil, nil, nil, false, time.Now(), time.Now()).
+			AddRow("ref-3", "student-1", "mongo-3", model.StatusDraft, nil, nil, nil, nil, false, time.Now(), time.Now()))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`points IS DISTINCT FROM \$1`).
+		WithArgs(25, "mongo-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "now"}).AddRow("ref-1", time.Now()))
+	mock.ExpectExec(`INSERT INTO achievement_outbox`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := svc.Recalculate(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Checked != 2 || result.Updated != 1 || result.Missing != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,182 @@
+package testing
+
+import (
+	"PROJECTUAS_BE/app/storage"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 adalah stand-in S3 / MinIO minimal di memori: PUT, GET (dengan Range), HEAD dan DELETE
+// pada path-style URL /<bucket>/<key>, serta ListObjectsV2. Signature tidak diperiksa.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeS3Object
+}
+
+type fakeS3Object struct {
+	data        []byte
+	contentType string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := r.URL.Path
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = fakeS3Object{data: data, contentType: r.Header.Get("Content-Type")}
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodGet, http.MethodHead:
+		if r.URL.Query().Get("list-type") == "2" {
+			f.list(w, strings.TrimSuffix(key, "/"), r.URL.Query().Get("prefix"))
+			return
+		}
+
+		obj, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				fmt.Fprintf(w, `<Error><Code>NoSuchKey</Code><Message>missing</Message><Key>%s</Key></Error>`, key)
+			}
+			return
+		}
+
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(obj.data))
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// list menjawab ListObjectsV2 dalam satu halaman
+func (f *fakeS3) list(w http.ResponseWriter, bucket string, prefix string) {
+	keys := []string{}
+	for k := range f.objects {
+		if key, ok := strings.CutPrefix(k, bucket+"/"); ok && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprintf(w, `<ListBucketResult><Name>%s</Name><Prefix>%s</Prefix><KeyCount>%d</KeyCount><MaxKeys>1000</MaxKeys><IsTruncated>false</IsTruncated>`,
+		strings.TrimPrefix(bucket, "/"), prefix, len(keys))
+	for _, key := range keys {
+		fmt.Fprintf(w, `<Contents><Key>%s</Key><LastModified>%s</LastModified><ETag>"etag"</ETag><Size>%d</Size></Contents>`,
+			key, time.Now().UTC().Format("2006-01-02T15:04:05.000Z"), len(f.objects[bucket+"/"+key].data))
+	}
+	fmt.Fprint(w, `</ListBucketResult>`)
+}
+
+func newFakeS3Store(t *testing.T) (*storage.S3Store, *fakeS3) {
+	t.Helper()
+
+	fake := &fakeS3{objects: map[string]fakeS3Object{}}
+	server := httptest.NewTLSServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := storage.NewS3Store(storage.S3Config{
+		Endpoint:  strings.TrimPrefix(server.URL, "https://"),
+		AccessKey: "minio",
+		SecretKey: "minio123",
+		Bucket:    "attachments",
+		UseSSL:    true,
+		Transport: server.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store, fake
+}
+
+// blobStoreContract dijalankan untuk setiap implementasi BlobStore
+func blobStoreContract(t *testing.T, store storage.BlobStore) {
+	ctx := context.Background()
+	content := []byte("%PDF-1.4 sertifikat lomba")
+
+	if err := store.Put(ctx, "achievements/a.pdf", bytes.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	obj, err := store.Open(ctx, "achievements/a.pdf")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if obj.Size != int64(len(content)) || obj.ContentType != "application/pdf" {
+		t.Fatalf("unexpected object metadata: size=%d type=%q", obj.Size, obj.ContentType)
+	}
+
+	if _, err := obj.Body.Seek(5, io.SeekStart); err != nil {
+		t.Fatalf("seek: %v", err)
+	}
+	rest, err := io.ReadAll(obj.Body)
+	obj.Body.Close()
+	if err != nil || string(rest) != string(content[5:]) {
+		t.Fatalf("unexpected content after seek: %q, %v", rest, err)
+	}
+
+	other := []byte("lain")
+	if err := store.Put(ctx, "exports/b.csv", bytes.NewReader(other), int64(len(other)), "text/csv"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	blobs, err := store.List(ctx, "achievements/")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(blobs) != 1 || blobs[0].Key != "achievements/a.pdf" || blobs[0].Size != int64(len(content)) || blobs[0].ModTime.IsZero() {
+		t.Fatalf("unexpected list result: %+v", blobs)
+	}
+
+	if err := store.Delete(ctx, "exports/b.csv"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := store.Delete(ctx, "achievements/a.pdf"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Open(ctx, "achievements/a.pdf"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+
+	for _, key := range []string{"../etc/passwd", "/abs.pdf", "a/../../b.pdf", ""} {
+		if err := store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), ""); !errors.Is(err, storage.ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey for %q, got %v", key, err)
+		}
+	}
+}
+
+func TestLocalStore_Contract(t *testing.T) {
+	blobStoreContract(t, storage.NewLocalStore(t.TempDir()))
+}
+
+func TestS3Store_Contract(t *testing.T) {
+	store, fake := newFakeS3Store(t)
+	blobStoreContract(t, store)
+
+	if len(fake.objects) != 0 {
+		t.Fatalf("expected bucket to be empty, got %d objects", len(fake.objects))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"PROJECTUAS_BE/app/repository"
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -100,7 +101,7 @@ func TestGetAllStudents_Success(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{
 		"student_id", "user_id", "academic_year",
-		"program_study", "username", "full_name", "email",
+		"program_study", "username", "full_name", "email", "sort_key", "row_id",
 	}).AddRow(
 		"student-1",
 		"user-1",
@@ -109,51 +110,82 @@ func TestGetAllStudents_Success(t *testing.T) {
 		"user1",
 		"User One",
 		"user1@gmail.com",
+		"User One",
+		"student-1",
 	)
 
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM students`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 	mock.ExpectQuery(`FROM students`).
 		WillReturnRows(rows)
 
-	students, err := repo.GetAllStudents()
+	students, page, err := repo.GetAllStudents(context.Background(), model.StudentFilter{}, model.ListQuery{Limit: 20, Page: 1})
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if len(students) != 1 {
+	if len(students) != 1 || page.Total != 1 {
 		t.Fatalf("expected 1 student")
 	}
 }
 
-func TestSubmitAchievement_Success(t *testing.T) {
+func TestGetAllStudents_Cursor(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
 
 	repo := repository.NewStudentRepository(db)
 
-	ref := &model.AchievementReference{
-		ID:                 "ref-1",
-		StudentID:          "student-1",
-		MongoAchievementID: "mongo-1",
-		Status:             "submitted",
-		SubmittedAt:        nil,
-	}
-
-	mock.ExpectExec(`INSERT INTO achievement_references`).
-		WithArgs(
-			ref.ID,
-			ref.StudentID,
-			ref.MongoAchievementID,
-			ref.Status,
-			ref.SubmittedAt,
-		).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	columns := []string{
+		"student_id", "user_id", "academic_year",
+		"program_study", "username", "full_name", "email", "sort_key", "row_id",
+	}
+	rows := sqlmock.NewRows(columns).
+		AddRow("student-2", "user-2", "2022", "Informatics", "user2", "Budi", "b@gmail.com", "2022", "student-2").
+		AddRow("student-3", "user-3", "2022", "Informatics", "user3", "Cici", "c@gmail.com", "2022", "student-3")
 
-	err := repo.Submit(context.Background(), ref)
+	cursor := model.EncodeCursor("2023", "student-1")
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM students s JOIN users u ON u.id = s.user_id WHERE s.program_study = \$1`).
+		WithArgs("Informatics").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	mock.ExpectQuery(`WHERE s.program_study = \$1 AND \(s.academic_year, s.id\) < \(\$2, \$3\) ORDER BY s.academic_year DESC, s.id DESC LIMIT 2 OFFSET 0`).
+		WithArgs("Informatics", "2023", "student-1").
+		WillReturnRows(rows)
+
+	students, page, err := repo.GetAllStudents(
+		context.Background(),
+		model.StudentFilter{ProgramStudy: "Informatics"},
+		model.ListQuery{Limit: 1, Cursor: cursor, Sort: "academic_year", Desc: true},
+	)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+
+	if len(students) != 1 || students[0].StudentID != "student-2" {
+		t.Fatalf("expected only student-2, got %+v", students)
+	}
+	if !page.HasMore || page.Total != 5 || page.Page != 0 {
+		t.Fatalf("unexpected page info: %+v", page)
+	}
+
+	next, err := model.DecodeCursor(page.NextCursor)
+	if err != nil || next.Value != "2022" || next.ID != "student-2" {
+		t.Fatalf("unexpected next cursor: %+v (%v)", next, err)
+	}
+}
+
+func TestGetAllStudents_InvalidSort(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewStudentRepository(db)
+
+	_, _, err := repo.GetAllStudents(context.Background(), model.StudentFilter{}, model.ListQuery{Limit: 20, Page: 1, Sort: "password_hash"})
+	if !errors.Is(err, repository.ErrInvalid) {
+		t.Fatalf("expected invalid sort error, got %v", err)
+	}
 }
 
 func TestGetStudentIDByUserID_Success(t *testing.T) {
@@ -203,3 +235,27 @@ func TestUpdateAdvisor_Success(t *testing.T) {
 		t.Fatalf("unexpected error")
 	}
 }
+
+func TestGetAdviseeUserIDs_Success(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewStudentRepository(db)
+
+	mock.ExpectQuery(`JOIN lecturers l ON l.id = s.advisor_id`).
+		WithArgs("lecturer-user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("user-1").AddRow("user-2"))
+
+	ids, err := repo.GetAdviseeUserIDs(context.Background(), "lecturer-user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(ids) != 2 || ids[0] != "user-1" || ids[1] != "user-2" {
+		t.Fatalf("unexpected advisees: %v", ids)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
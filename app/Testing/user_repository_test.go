@@ -0,0 +1,148 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCreateUserWithProfile_Student(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+
+	req := &model.CreateUserRequest{
+		Username: "student1",
+		Email:    "student1@gmail.com",
+		RoleID:   "role-student",
+		Fullname: "Student One",
+		Student: &model.StudentProfileRequest{
+			AcademicYear: "2022",
+			ProgramStudy: "Informatics",
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT name FROM roles`).
+		WithArgs("role-student").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("mahasiswa"))
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs("student1", "student1@gmail.com", "hashed", "role-student", "Student One").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "is_active"}).AddRow("user-1", true))
+	mock.ExpectQuery(`INSERT INTO students`).
+		WithArgs("user-1", "2022", "Informatics", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("student-1"))
+	mock.ExpectCommit()
+
+	profile, err := repo.CreateUserWithProfile(context.Background(), req, "hashed")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if profile.Student == nil || profile.Student.ID != "student-1" {
+		t.Fatal("expected student profile to be created")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateUserWithProfile_RollbackOnProfileFailure(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+
+	req := &model.CreateUserRequest{
+		Username: "lecturer1",
+		Email:    "lecturer1@gmail.com",
+		RoleID:   "role-lecturer",
+		Fullname: "Lecturer One",
+		Lecturer: &model.LecturerProfileRequest{Department: "Informatics"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT name FROM roles`).
+		WithArgs("role-lecturer").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("dosen"))
+	mock.ExpectQuery(`INSERT INTO users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "is_active"}).AddRow("user-2", true))
+	mock.ExpectQuery(`INSERT INTO lecturers`).
+		WithArgs("user-2", "Informatics").
+		WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	profile, err := repo.CreateUserWithProfile(context.Background(), req, "hashed")
+
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	if profile != nil {
+		t.Fatal("expected nil profile")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateUserWithProfile_MissingStudentProfile(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+
+	req := &model.CreateUserRequest{
+		Username: "student2",
+		Email:    "student2@gmail.com",
+		RoleID:   "role-student",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT name FROM roles`).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("mahasiswa"))
+	mock.ExpectRollback()
+
+	_, err := repo.CreateUserWithProfile(context.Background(), req, "hashed")
+
+	if err != repository.ErrStudentProfileEmpty {
+		t.Fatalf("expected ErrStudentProfileEmpty, got %v", err)
+	}
+}
+
+// role tanpa tabel profil (selain admin) ditolak sebelum user dibuat
+func TestCreateUserWithProfile_UnknownRole(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+
+	req := &model.CreateUserRequest{
+		Username: "staff1",
+		Email:    "staff1@gmail.com",
+		RoleID:   "role-staff",
+		Student:  &model.StudentProfileRequest{AcademicYear: "2022", ProgramStudy: "Informatics"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT name FROM roles`).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("student"))
+	mock.ExpectRollback()
+
+	_, err := repo.CreateUserWithProfile(context.Background(), req, "hashed")
+
+	if err != repository.ErrUnsupportedRole {
+		t.Fatalf("expected ErrUnsupportedRole, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
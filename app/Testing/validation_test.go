@@ -0,0 +1,129 @@
+package testing
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/response"
+	"PROJECTUAS_BE/app/validation"
+	"PROJECTUAS_BE/middleware"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func fieldRules(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	var fieldErrs validation.Errors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("expected validation.Errors, got %v", err)
+	}
+
+	rules := map[string]string{}
+	for _, f := range fieldErrs {
+		rules[f.Field] = f.Rule
+	}
+	return rules
+}
+
+func TestValidation_CreateUserRequest(t *testing.T) {
+	req := model.CreateUserRequest{
+		Username: "ab",
+		Email:    "not-an-email",
+		Password: "secret",
+		RoleID:   "admin",
+		Student:  &model.StudentProfileRequest{ProgramStudy: "TI"},
+	}
+
+	rules := fieldRules(t, validation.Struct(&req))
+
+	expected := map[string]string{
+		"username":              "min",
+		"email":                 "email",
+		"password_hash":         "min",
+		"role_id":               "uuid",
+		"student.academic_year": "required",
+	}
+	for field, rule := range expected {
+		if rules[field] != rule {
+			t.Errorf("expected %s to fail on %q, got %q", field, rule, rules[field])
+		}
+	}
+	if len(rules) != len(expected) {
+		t.Errorf("expected %d field errors, got %v", len(expected), rules)
+	}
+}
+
+func TestValidation_VerifyRequest(t *testing.T) {
+	if err := validation.Struct(&model.VerifyRequest{Status: "verified"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rules := fieldRules(t, validation.Struct(&model.VerifyRequest{Status: "rejected"}))
+	if rules["rejection_reason"] != "required_if" {
+		t.Fatalf("expected rejection_reason required_if, got %v", rules)
+	}
+
+	rules = fieldRules(t, validation.Struct(&model.VerifyRequest{Status: "approved"}))
+	if rules["status"] != "oneof" {
+		t.Fatalf("expected status oneof, got %v", rules)
+	}
+}
+
+func TestValidation_CompetitionDetails(t *testing.T) {
+	d := &model.CompetitionDetails{
+		CompetitionName:  "Gemastik",
+		CompetitionLevel: "national",
+		Rank:             -1,
+		EventDate:        time.Now().AddDate(0, 1, 0),
+	}
+
+	rules := fieldRules(t, d.Validate())
+	if rules["rank"] != "min" || rules["eventDate"] != "notfuture" {
+		t.Fatalf("unexpected rules: %v", rules)
+	}
+}
+
+func TestErrorHandler_ValidationResponse(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Post("/", func(c *fiber.Ctx) error {
+		var req model.ScoringRuleRequest
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.ErrBadRequest
+		}
+		return validation.Struct(&req)
+	})
+
+	body := `{"achievement_type": "sport", "points": -5}`
+	httpReq := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(httpReq)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.StatusCode != fiber.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", resp.StatusCode)
+	}
+
+	raw, _ := io.ReadAll(resp.Body)
+	var out response.Envelope
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("invalid json %s: %v", raw, err)
+	}
+
+	if out.Success || out.Error == nil || out.Error.Code != response.CodeValidationFailed {
+		t.Fatalf("unexpected body: %s", raw)
+	}
+
+	details := out.Error.Details
+	if len(details) != 2 || details[0].Field != "achievement_type" || details[1].Field != "points" {
+		t.Fatalf("unexpected fields: %+v", details)
+	}
+}
@@ -0,0 +1,330 @@
+// Package preview membuat thumbnail JPEG untuk lampiran gambar dan PDF tanpa layanan eksternal.
+package preview
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"regexp"
+	"strconv"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	ContentType = "image/jpeg"
+	Ext         = ".jpg"
+
+	MaxSide   = 320        // sisi terpanjang thumbnail dalam pixel
+	maxPixels = 40_000_000 // batas ukuran gambar sumber, mencegah decompression bomb
+)
+
+// ErrUnavailable: preview tidak bisa dibuat, misalnya PDF tanpa gambar atau format yang tidak didukung
+var ErrUnavailable = errors.New("preview not available")
+
+// Generate membuat thumbnail JPEG dari isi file yang sudah lolos AttachmentPolicy.
+func Generate(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case "image/png", "image/jpeg":
+		return fromImage(data)
+	case "application/pdf":
+		return fromPDF(data)
+	}
+	return nil, ErrUnavailable
+}
+
+func fromImage(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || !sizeOK(cfg.Width, cfg.Height) {
+		return nil, ErrUnavailable
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnavailable
+	}
+	return thumbnail(img)
+}
+
+func sizeOK(width int, height int) bool {
+	return width > 0 && height > 0 && width*height <= maxPixels
+}
+
+// thumbnail mengecilkan gambar (tidak pernah memperbesar) di atas latar putih,
+// sehingga PNG transparan tidak menjadi hitam di JPEG.
+func thumbnail(src image.Image) ([]byte, error) {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if !sizeOK(width, height) {
+		return nil, ErrUnavailable
+	}
+
+	scale := min(1, float64(MaxSide)/float64(max(width, height)))
+	dst := image.NewRGBA(image.Rect(0, 0, max(1, int(float64(width)*scale)), max(1, int(float64(height)*scale))))
+
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var (
+	pdfObject      = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	pdfRoot        = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R`)
+	pdfRef         = regexp.MustCompile(`^\s*(\d+)\s+\d+\s+R`)
+	pdfRefs        = regexp.MustCompile(`(\d+)\s+\d+\s+R`)
+	pdfKids        = regexp.MustCompile(`/Kids\s*\[([^\]]*)\]`)
+	pdfTypePages   = regexp.MustCompile(`/Type\s*/Pages\b`)
+	pdfStream      = regexp.MustCompile(`^\s*stream\r?\n`)
+	pdfSubtypeImg  = regexp.MustCompile(`/Subtype\s*/Image\b`)
+	pdfWidth       = regexp.MustCompile(`/Width\s+(\d+)`)
+	pdfHeight      = regexp.MustCompile(`/Height\s+(\d+)`)
+	pdfBits        = regexp.MustCompile(`/BitsPerComponent\s+(\d+)`)
+	pdfColorSpace  = regexp.MustCompile(`/ColorSpace\s*/(DeviceRGB|DeviceGray)\b`)
+	pdfDCT         = regexp.MustCompile(`/Filter\s*(/DCTDecode\b|\[\s*/DCTDecode\s*\])`)
+	pdfFlate       = regexp.MustCompile(`/Filter\s*(/FlateDecode\b|\[\s*/FlateDecode\s*\])`)
+	pdfDecodeParms = regexp.MustCompile(`/DecodeParms\b`)
+)
+
+// maxPageTreeDepth membatasi penelusuran /Kids, mencegah loop di PDF yang rusak
+const maxPageTreeDepth = 32
+
+// pdfImage adalah satu image XObject di dalam PDF, width / height dari dictionary-nya
+type pdfImage struct {
+	dict          []byte
+	stream        []byte
+	width, height int
+}
+
+// pdfDocument: posisi awal isi setiap objek (setelah "N G obj"); definisi terakhir menang,
+// sesuai incremental update. Objek di dalam object stream (PDF 1.5+) tidak terbaca.
+type pdfDocument struct {
+	data    []byte
+	objects map[int]int
+}
+
+// fromPDF tidak merender halaman PDF. Sertifikat umumnya hasil scan atau desain yang
+// diekspor sebagai satu gambar halaman penuh, sehingga gambar terbesar yang dipakai
+// halaman pertama (/Resources /XObject) dijadikan preview. Teks dan vektor tidak
+// digambar, PDF yang halaman pertamanya tanpa gambar tidak punya preview.
+func fromPDF(data []byte) ([]byte, error) {
+	doc := pdfDocument{data: data, objects: map[int]int{}}
+	for _, loc := range pdfObject.FindAllSubmatchIndex(data, -1) {
+		n, _ := strconv.Atoi(string(data[loc[2]:loc[3]]))
+		doc.objects[n] = loc[1]
+	}
+
+	var best image.Image
+	for _, p := range doc.firstPageImages() {
+		if !sizeOK(p.width, p.height) {
+			continue
+		}
+		if best != nil && p.width*p.height <= best.Bounds().Dx()*best.Bounds().Dy() {
+			continue
+		}
+
+		if img, err := p.decode(); err == nil {
+			best = img
+		}
+	}
+
+	if best == nil {
+		return nil, ErrUnavailable
+	}
+	return thumbnail(best)
+}
+
+// firstPageImages menelusuri Catalog → /Pages → /Kids pertama sampai halaman pertama,
+// lalu mengambil image XObject dari /Resources-nya (boleh diwarisi dari node /Pages)
+func (d pdfDocument) firstPageImages() []pdfImage {
+	roots := pdfRoot.FindAllSubmatch(d.data, -1)
+	if len(roots) == 0 {
+		return nil
+	}
+	catalog := d.object(roots[len(roots)-1][1])
+
+	node := d.entry(catalog, "Pages")
+	var resources []byte
+	for depth := 0; node != nil && depth < maxPageTreeDepth; depth++ {
+		if r := d.entry(node, "Resources"); r != nil {
+			resources = r
+		}
+		if !pdfTypePages.Match(node) {
+			break // halaman pertama
+		}
+
+		kids := pdfKids.FindSubmatch(node)
+		if kids == nil {
+			return nil
+		}
+		first := pdfRefs.FindSubmatch(kids[1])
+		if first == nil {
+			return nil
+		}
+		node = d.object(first[1])
+	}
+	if node == nil || pdfTypePages.Match(node) {
+		return nil
+	}
+
+	xobjects := d.entry(resources, "XObject")
+	if xobjects == nil {
+		return nil
+	}
+
+	var images []pdfImage
+	for _, ref := range pdfRefs.FindAllSubmatch(xobjects, -1) {
+		start, ok := d.start(ref[1])
+		if !ok {
+			continue
+		}
+
+		dict := pdfDict(d.data, start)
+		if dict == nil || !pdfSubtypeImg.Match(dict) {
+			continue
+		}
+
+		body := d.data[start:]
+		offset := bytes.Index(body, dict) + len(dict)
+		header := pdfStream.Find(body[offset:])
+		if header == nil {
+			continue
+		}
+		offset += len(header)
+
+		end := bytes.Index(body[offset:], []byte("endstream"))
+		if end < 0 {
+			continue
+		}
+
+		images = append(images, pdfImage{
+			dict:   dict,
+			stream: body[offset : offset+end],
+			width:  pdfInt(pdfWidth, dict),
+			height: pdfInt(pdfHeight, dict),
+		})
+	}
+	return images
+}
+
+func (d pdfDocument) start(number []byte) (int, bool) {
+	n, err := strconv.Atoi(string(number))
+	if err != nil {
+		return 0, false
+	}
+	start, ok := d.objects[n]
+	return start, ok
+}
+
+// object mengembalikan dictionary objek bernomor number, nil jika tidak ada
+func (d pdfDocument) object(number []byte) []byte {
+	start, ok := d.start(number)
+	if !ok {
+		return nil
+	}
+	return pdfDict(d.data, start)
+}
+
+// entry mengembalikan nilai /key di dict: dictionary langsung << >> atau objek yang dirujuk "N G R"
+func (d pdfDocument) entry(dict []byte, key string) []byte {
+	loc := regexp.MustCompile(`/` + key + `\b`).FindIndex(dict)
+	if loc == nil {
+		return nil
+	}
+
+	rest := dict[loc[1]:]
+	if ref := pdfRef.FindSubmatch(rest); ref != nil {
+		return d.object(ref[1])
+	}
+	return pdfDict(rest, 0)
+}
+
+// pdfDict mengambil dictionary << >> (beserta dictionary bersarang) yang dimulai di data[from:]
+func pdfDict(data []byte, from int) []byte {
+	start := from
+	for start < len(data) && bytes.IndexByte([]byte(" \t\r\n"), data[start]) >= 0 {
+		start++
+	}
+	if !bytes.HasPrefix(data[start:], []byte("<<")) {
+		return nil
+	}
+
+	depth := 0
+	for i := start; i+1 < len(data); i++ {
+		switch {
+		case data[i] == '<' && data[i+1] == '<':
+			depth++
+			i++
+		case data[i] == '>' && data[i+1] == '>':
+			depth--
+			i++
+			if depth == 0 {
+				return data[start : i+1]
+			}
+		}
+	}
+	return nil
+}
+
+func pdfInt(re *regexp.Regexp, dict []byte) int {
+	m := re.FindSubmatch(dict)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(string(m[1]))
+	return n
+}
+
+// decode mendukung JPEG (DCTDecode) dan pixel mentah 8 bit RGB / gray (FlateDecode tanpa predictor)
+func (p pdfImage) decode() (image.Image, error) {
+	if pdfDCT.Match(p.dict) {
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(p.stream))
+		if err != nil || !sizeOK(cfg.Width, cfg.Height) {
+			return nil, ErrUnavailable
+		}
+		return jpeg.Decode(bytes.NewReader(p.stream))
+	}
+
+	if !pdfFlate.Match(p.dict) || pdfDecodeParms.Match(p.dict) || pdfInt(pdfBits, p.dict) != 8 {
+		return nil, ErrUnavailable
+	}
+	space := pdfColorSpace.FindSubmatch(p.dict)
+	if space == nil {
+		return nil, ErrUnavailable
+	}
+
+	components := 3
+	if string(space[1]) == "DeviceGray" {
+		components = 1
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(p.stream))
+	if err != nil {
+		return nil, ErrUnavailable
+	}
+	defer zr.Close()
+
+	pix := make([]byte, p.width*p.height*components)
+	if _, err := io.ReadFull(zr, pix); err != nil {
+		return nil, ErrUnavailable
+	}
+
+	rect := image.Rect(0, 0, p.width, p.height)
+	if components == 1 {
+		return &image.Gray{Pix: pix, Stride: p.width, Rect: rect}, nil
+	}
+
+	img := image.NewRGBA(rect)
+	for i, j := 0, 0; i < len(pix); i, j = i+3, j+4 {
+		img.Pix[j], img.Pix[j+1], img.Pix[j+2], img.Pix[j+3] = pix[i], pix[i+1], pix[i+2], 0xff
+	}
+	return img, nil
+}
@@ -3,22 +3,43 @@ package repository
 import (
 	model "PROJECTUAS_BE/app/Model"
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrAttachmentRejected: batas jumlah tercapai atau file yang sama sudah dilampirkan
+// oleh upload lain yang berjalan bersamaan
+var ErrAttachmentRejected = Conflict("attachment limit reached or file already attached")
+
+// ErrAttachmentChanged: lampiran sudah dihapus / diganti request lain
+var ErrAttachmentChanged = Conflict("attachment was changed by another request, please retry")
+
 type AchievementRepository interface {
-	GetAll() ([]model.Achievement, error)
+	EnsureIndexes(ctx context.Context) error
+	List(ctx context.Context, filter model.AchievementFilter, q model.ListQuery) ([]model.Achievement, model.PageInfo, error)
+	Search(ctx context.Context, search model.AchievementSearch, q model.ListQuery) ([]model.AchievementSearchHit, model.PageInfo, error)
 	GetAchievementByID(id string) (*model.Achievement, error)
 	Create(ctx context.Context, achieve *model.Achievement) error
 	FindById(ctx context.Context, id string) (*model.Achievement, error)
 	Update(ctx context.Context, id string, update bson.M) error
 	Delete(ctx context.Context, id string) error
-	GetStudentByAchievement(ctx context.Context, studentID string) ([]*model.Achievement, error)
-	AddAttachment(ctx context.Context, achievementID string, attachment model.Attachment) error
+	AddAttachment(ctx context.Context, achievementID string, attachment model.Attachment, maxCount int) error
+	RemoveAttachment(ctx context.Context, achievementID string, attachment model.Attachment) error
+	ReplaceAttachment(ctx context.Context, achievementID string, old model.Attachment, replacement model.Attachment) error
+	ListAttachmentKeys(ctx context.Context) (map[string]bool, error)
+	ListOwners(ctx context.Context) (map[string]string, error)
+	FindByIDs(ctx context.Context, ids []string) ([]model.Achievement, error)
+	AggregateStatistics(ctx context.Context, q model.StatisticsQuery) (*model.AchievementStatistics, error)
+	SumPointsByStudent(ctx context.Context, ids []string, filter model.LeaderboardFilter) ([]model.StudentPoints, error)
+	ApplyStatusSync(ctx context.Context, id string, sync model.AchievementStatusSync) error
+	ApplyPointsSync(ctx context.Context, id string, sync model.AchievementPointsSync) error
 }
 
 type AchievementMongoDB struct {
@@ -31,21 +52,225 @@ func NewAchievementMongo(db *mongo.Database) AchievementRepository {
 	}
 }
 
-func (r *AchievementMongoDB) GetAll() ([]model.Achievement, error) {
-	ctx := context.TODO()
+// EnsureIndexes membuat index yang dibutuhkan query, aman dipanggil setiap start.
+// Text index dipakai oleh Search; weight menentukan field mana yang paling relevan.
+func (r *AchievementMongoDB) EnsureIndexes(ctx context.Context) error {
+	_, err := r.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "details.competitionName", Value: "text"},
+			{Key: "details.organizer", Value: "text"},
+			{Key: "tags", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("achievements_text").
+			SetDefaultLanguage("none"). // judul campuran Indonesia/Inggris, tanpa stemming
+			SetWeights(bson.D{
+				{Key: "title", Value: 10},
+				{Key: "details.competitionName", Value: 8},
+				{Key: "tags", Value: 5},
+				{Key: "details.organizer", Value: 3},
+				{Key: "description", Value: 1},
+			}),
+	})
+	return err
+}
+
+// Search mencari prestasi dengan text index, urut dari score relevansi tertinggi.
+// Hanya paginasi offset: score tidak stabil untuk dijadikan cursor.
+func (r *AchievementMongoDB) Search(ctx context.Context, search model.AchievementSearch, q model.ListQuery) ([]model.AchievementSearchHit, model.PageInfo, error) {
+	info := model.PageInfo{Limit: q.Limit, Page: q.Page}
+	hits := []model.AchievementSearchHit{}
 
-	cursor, err := r.Collection.Find(ctx, bson.M{})
+	if search.Owners != nil && len(search.Owners) == 0 {
+		return hits, info, nil
+	}
+
+	filter := bson.M{"$text": bson.M{"$search": search.Text}}
+	if search.Owners != nil {
+		filter["studentId"] = bson.M{"$in": search.Owners}
+	}
+
+	total, err := r.Collection.CountDocuments(ctx, filter)
 	if err != nil {
-		return nil, err
+		return nil, info, err
+	}
+	info.Total = total
+
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(q.Offset())).
+		SetLimit(int64(q.Limit + 1))
+
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, info, err
 	}
 	defer cursor.Close(ctx)
 
-	var achievements []model.Achievement
+	if err := cursor.All(ctx, &hits); err != nil {
+		return nil, info, err
+	}
+
+	if len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+		info.HasMore = true
+	}
+
+	return hits, info, nil
+}
+
+// achievementSorts: nama di query string → field dokumen beserta tipe nilainya untuk cursor
+var achievementSorts = map[string]struct {
+	field string
+	kind  string // time | string | int
+}{
+	"created_at": {"createdAt", "time"},
+	"updated_at": {"updatedAt", "time"},
+	"title":      {"title", "string"},
+	"points":     {"points", "int"},
+}
+
+// List membaca prestasi dengan filter, sorting dan paginasi offset / keyset.
+// Default urut createdAt terbaru lebih dulu diatur oleh service (sort=-created_at).
+func (r *AchievementMongoDB) List(ctx context.Context, f model.AchievementFilter, q model.ListQuery) ([]model.Achievement, model.PageInfo, error) {
+	info := model.PageInfo{Limit: q.Limit}
+	if q.Cursor == "" {
+		info.Page = q.Page
+	}
+
+	sortName := q.Sort
+	if sortName == "" {
+		sortName = "created_at"
+	}
+	sort, ok := achievementSorts[sortName]
+	if !ok {
+		return nil, info, ErrInvalidSort
+	}
+
+	filter := bson.M{}
+	if f.Status != "" {
+		filter["status"] = f.Status
+	}
+	if f.AchievementType != "" {
+		filter["achievementType"] = f.AchievementType
+	}
+	if len(f.Tags) > 0 {
+		filter["tags"] = bson.M{"$all": f.Tags}
+	}
+	// $in kosong berarti tidak ada prestasi yang terlihat
+	studentID := bson.M{}
+	if f.StudentID != "" {
+		studentID["$eq"] = f.StudentID
+	}
+	if f.Owners != nil {
+		studentID["$in"] = f.Owners
+	}
+	if len(studentID) > 0 {
+		filter["studentId"] = studentID
+	}
+	if createdAt := dateRange(f.From, f.To); createdAt != nil {
+		filter["createdAt"] = createdAt
+	}
+
+	total, err := r.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, info, err
+	}
+	info.Total = total
+
+	direction, op := 1, "$gt"
+	if q.Desc {
+		direction, op = -1, "$lt"
+	}
+
+	if q.Cursor != "" {
+		cursor, err := model.DecodeCursor(q.Cursor)
+		if err != nil {
+			return nil, info, Invalid(err.Error())
+		}
+		value, err := parseSortValue(sort.kind, cursor.Value)
+		if err != nil {
+			return nil, info, Invalid(model.ErrInvalidCursor.Error())
+		}
+
+		// (field, _id) setelah item terakhir; _id ObjectID lama dibandingkan sebagai ObjectID
+		var lastID interface{} = cursor.ID
+		if objID, err := primitive.ObjectIDFromHex(cursor.ID); err == nil {
+			lastID = objID
+		}
+		filter = bson.M{"$and": bson.A{filter, bson.M{"$or": bson.A{
+			bson.M{sort.field: bson.M{op: value}},
+			bson.M{sort.field: value, "_id": bson.M{op: lastID}},
+		}}}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: sort.field, Value: direction}, {Key: "_id", Value: direction}}).
+		SetSkip(int64(q.Offset())).
+		SetLimit(int64(q.Limit + 1))
+
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, info, err
+	}
+	defer cursor.Close(ctx)
+
+	achievements := []model.Achievement{}
 	if err := cursor.All(ctx, &achievements); err != nil {
-		return nil, err
+		return nil, info, err
 	}
 
-	return achievements, nil
+	if len(achievements) > q.Limit {
+		achievements = achievements[:q.Limit]
+		last := achievements[len(achievements)-1]
+
+		info.HasMore = true
+		info.NextCursor = model.EncodeCursor(formatSortValue(sortName, last), last.ID)
+	}
+
+	return achievements, info, nil
+}
+
+func parseSortValue(kind string, value string) (interface{}, error) {
+	switch kind {
+	case "time":
+		return time.Parse(time.RFC3339Nano, value)
+	case "int":
+		return strconv.Atoi(value)
+	}
+	return value, nil
+}
+
+func formatSortValue(sortName string, a model.Achievement) string {
+	switch sortName {
+	case "created_at":
+		return a.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case "updated_at":
+		return a.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case "points":
+		return strconv.Itoa(a.Points)
+	}
+	return a.Title
+}
+
+// dateRange: filter createdAt [from, to), nil jika keduanya kosong
+func dateRange(from *time.Time, to *time.Time) bson.M {
+	if from == nil && to == nil {
+		return nil
+	}
+
+	r := bson.M{}
+	if from != nil {
+		r["$gte"] = *from
+	}
+	if to != nil {
+		r["$lt"] = *to
+	}
+	return r
 }
 
 func (r *AchievementMongoDB) GetAchievementByID(id string) (*model.Achievement, error) {
@@ -55,14 +280,9 @@ func (r *AchievementMongoDB) GetAchievementByID(id string) (*model.Achievement,
 	var achievement model.Achievement
 
 	// filter id bson
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return nil, err
-	}
-
-	filter := bson.M{"_id": objID}
+	filter := achievementIDFilter(id)
 
-	err = r.Collection.FindOne(ctx, filter).Decode(&achievement)
+	err := r.Collection.FindOne(ctx, filter).Decode(&achievement)
 	if err == mongo.ErrNoDocuments {
 		return nil, nil
 	}
@@ -82,7 +302,7 @@ func (r *AchievementMongoDB) Create(ctx context.Context, achieve *model.Achievem
 
 func (r *AchievementMongoDB) FindById(ctx context.Context, id string) (*model.Achievement, error) {
 	var achievement model.Achievement
-	err := r.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&achievement)
+	err := r.Collection.FindOne(ctx, achievementIDFilter(id)).Decode(&achievement)
 	if err != nil {
 		return nil, err
 	}
@@ -90,34 +310,155 @@ func (r *AchievementMongoDB) FindById(ctx context.Context, id string) (*model.Ac
 }
 
 func (r *AchievementMongoDB) Update(ctx context.Context, id string, update bson.M) error {
-	_, err := r.Collection.UpdateByID(ctx, id, bson.M{
+	_, err := r.Collection.UpdateOne(ctx, achievementIDFilter(id), bson.M{
 		"$set": update,
 	})
 	return err
 }
 
 func (r *AchievementMongoDB) Delete(ctx context.Context, id string) error {
-	objID, err := primitive.ObjectIDFromHex(id)
+	_, err := r.Collection.DeleteOne(ctx, achievementIDFilter(id))
+	return err
+}
+
+// AddAttachment menambah lampiran hanya jika jumlahnya masih di bawah maxCount dan
+// file dengan checksum yang sama belum ada. Kondisi dicek di filter agar aman dari upload paralel.
+func (r *AchievementMongoDB) AddAttachment(ctx context.Context, achievementID string, attachment model.Attachment, maxCount int) error {
+	filter := achievementIDFilter(achievementID)
+	filter[fmt.Sprintf("attachments.%d", maxCount-1)] = bson.M{"$exists": false}
+	if attachment.SHA256 != "" {
+		filter["attachments.sha256"] = bson.M{"$ne": attachment.SHA256}
+	}
+
+	update := bson.M{
+		"$push": bson.M{
+			"attachments": attachment,
+		},
+		"$set": bson.M{
+			"updatedAt": attachment.UploadedAt,
+		},
+	}
+
+	result, err := r.Collection.UpdateOne(
+		ctx,
+		filter,
+		update,
+	)
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return ErrAttachmentRejected
+	}
 
-	_, err = r.Collection.DeleteOne(ctx, bson.M{"_id": objID})
-	return err
+	return nil
+}
+
+// attachmentMatch mencocokkan satu lampiran di array attachments.
+// Lampiran lama belum punya id, sehingga dicocokkan lewat storageKey atau fileUrl.
+func attachmentMatch(a model.Attachment) bson.M {
+	switch {
+	case a.ID != "":
+		return bson.M{"id": a.ID}
+	case a.StorageKey != "":
+		return bson.M{"storageKey": a.StorageKey}
+	}
+	return bson.M{"fileUrl": a.FileUrl}
+}
+
+func (r *AchievementMongoDB) RemoveAttachment(ctx context.Context, achievementID string, attachment model.Attachment) error {
+	result, err := r.Collection.UpdateOne(ctx, achievementIDFilter(achievementID), bson.M{
+		"$pull": bson.M{"attachments": attachmentMatch(attachment)},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return ErrAttachmentChanged
+	}
+
+	return nil
+}
+
+// ReplaceAttachment menimpa satu elemen attachments dengan arrayFilters, posisi elemen tetap
+func (r *AchievementMongoDB) ReplaceAttachment(ctx context.Context, achievementID string, old model.Attachment, replacement model.Attachment) error {
+	match := attachmentMatch(old)
+
+	filter := achievementIDFilter(achievementID)
+	filter["attachments"] = bson.M{"$elemMatch": match}
+
+	elem := bson.M{}
+	for field, value := range match {
+		elem["a."+field] = value
+	}
+
+	result, err := r.Collection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"attachments.$[a]": replacement,
+			"updatedAt":        replacement.UploadedAt,
+		},
+	}, options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{elem}}))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrAttachmentChanged
+	}
+
+	return nil
 }
 
-func (r *AchievementMongoDB) GetStudentByAchievement(ctx context.Context, studentID string) ([]*model.Achievement, error) {
-	filter := bson.M{
-		"studentId": studentID,
+// ListAttachmentKeys mengembalikan semua key BlobStore (file dan preview) yang masih direferensikan dokumen prestasi
+func (r *AchievementMongoDB) ListAttachmentKeys(ctx context.Context) (map[string]bool, error) {
+	opts := options.Find().SetProjection(bson.M{"attachments": 1})
+
+	cursor, err := r.Collection.Find(ctx, bson.M{"attachments.0": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	keys := map[string]bool{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			Attachments []model.Attachment `bson:"attachments"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		for _, a := range doc.Attachments {
+			keys[a.Key()] = true
+			if a.PreviewKey != "" {
+				keys[a.PreviewKey] = true
+			}
+		}
 	}
 
-	cursor, err := r.Collection.Find(ctx, filter)
+	return keys, cursor.Err()
+}
+
+// FindByIDs mengambil banyak prestasi sekaligus dengan satu query $in
+func (r *AchievementMongoDB) FindByIDs(ctx context.Context, ids []string) ([]model.Achievement, error) {
+	if len(ids) == 0 {
+		return []model.Achievement{}, nil
+	}
+
+	values := bson.A{}
+	for _, id := range ids {
+		values = append(values, id)
+		if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+			values = append(values, objID)
+		}
+	}
+
+	cursor, err := r.Collection.Find(ctx, bson.M{"_id": bson.M{"$in": values}})
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var achievements []*model.Achievement
+	var achievements []model.Achievement
 	if err := cursor.All(ctx, &achievements); err != nil {
 		return nil, err
 	}
@@ -125,22 +466,321 @@ func (r *AchievementMongoDB) GetStudentByAchievement(ctx context.Context, studen
 	return achievements, nil
 }
 
-func (r *AchievementMongoDB) AddAttachment(ctx context.Context, achievementID string, attachment model.Attachment) error {
-	update := bson.M{
-		"$push": bson.M{
-			"attachments": attachment,
-		},
-		"$set": bson.M{
-			"updatedAt": attachment.UploadedAt,
-		},
+// statisticsBatchSize: jumlah id prestasi per pipeline, agar $in / $switch tidak tumbuh
+// mengikuti ukuran koleksi
+const statisticsBatchSize = 1000
+
+// AggregateStatistics menghitung statistik dengan pipeline $facet.
+// Status diambil dari PostgreSQL (q.Statuses), bukan dari field status hasil outbox yang bisa
+// tertinggal. Id dikirim per batch statisticsBatchSize lalu hasil tiap batch dijumlahkan.
+// Periode dihitung dari createdAt.
+func (r *AchievementMongoDB) AggregateStatistics(ctx context.Context, q model.StatisticsQuery) (*model.AchievementStatistics, error) {
+	stats := &model.AchievementStatistics{
+		ByType:   map[string]int{},
+		ByLevel:  map[string]int{},
+		ByMedal:  map[string]int{},
+		ByStatus: map[string]int{},
+		Period:   q.Period,
+		From:     q.From,
+		To:       q.To,
+		Series:   []model.StatisticsPoint{},
 	}
 
-	_, err := r.Collection.UpdateOne(
-		ctx,
-		bson.M{"_id": achievementID},
-		update,
-	)
+	ids := make([]string, 0, len(q.Statuses))
+	for id := range q.Statuses {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	series := map[string]*model.StatisticsPoint{}
+	for start := 0; start < len(ids); start += statisticsBatchSize {
+		end := min(start+statisticsBatchSize, len(ids))
+		if err := r.aggregateStatisticsBatch(ctx, q, ids[start:end], stats, series); err != nil {
+			return nil, err
+		}
+	}
+
+	stats.Submitted = stats.ByStatus[model.StatusSubmitted]
+	stats.Verified = stats.ByStatus[model.StatusVerified]
+	stats.Rejected = stats.ByStatus[model.StatusRejected]
+
+	for _, point := range series {
+		stats.Series = append(stats.Series, *point)
+	}
+	sort.Slice(stats.Series, func(i, j int) bool {
+		return stats.Series[i].Period < stats.Series[j].Period
+	})
+
+	return stats, nil
+}
+
+// aggregateStatisticsBatch menjalankan pipeline untuk satu batch id dan menambahkan hasilnya ke stats
+func (r *AchievementMongoDB) aggregateStatisticsBatch(ctx context.Context, q model.StatisticsQuery, ids []string, stats *model.AchievementStatistics, series map[string]*model.StatisticsPoint) error {
+	// kelompokkan id per status, termasuk bentuk ObjectID untuk data lama
+	idsByStatus := map[string]bson.A{}
+	all := bson.A{}
+	for _, id := range ids {
+		values := bson.A{id}
+		if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+			values = append(values, objID)
+		}
+		status := q.Statuses[id]
+		idsByStatus[status] = append(idsByStatus[status], values...)
+		all = append(all, values...)
+	}
+
+	branches := bson.A{}
+	for status, statusIDs := range idsByStatus {
+		branches = append(branches, bson.M{
+			"case": bson.M{"$in": bson.A{"$_id", statusIDs}},
+			"then": status,
+		})
+	}
+
+	match := bson.M{"_id": bson.M{"$in": all}}
+	if createdAt := dateRange(q.From, q.To); createdAt != nil {
+		match["createdAt"] = createdAt
+	}
+
+	period := bson.M{"$dateToString": bson.M{"format": "%Y-%m", "date": "$createdAt"}}
+	if q.Period == model.PeriodSemester {
+		period = bson.M{"$concat": bson.A{
+			bson.M{"$toString": bson.M{"$year": "$createdAt"}},
+			bson.M{"$cond": bson.A{bson.M{"$lte": bson.A{bson.M{"$month": "$createdAt"}, 6}}, "-S1", "-S2"}},
+		}}
+	}
+
+	countBy := func(field string) bson.A {
+		return bson.A{
+			bson.M{"$match": bson.M{field: bson.M{"$nin": bson.A{nil, ""}}}},
+			bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{
+			"refStatus": bson.M{"$switch": bson.M{"branches": branches}},
+			"period":    period,
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"byType":   countBy("achievementType"),
+			"byLevel":  countBy("details.competitionLevel"),
+			"byMedal":  countBy("details.medalType"),
+			"byStatus": countBy("refStatus"),
+			"series": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":   bson.M{"period": "$period", "status": "$refStatus"},
+					"count": bson.M{"$sum": 1},
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := r.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	type bucket struct {
+		ID    string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+
+	var result []struct {
+		ByType   []bucket `bson:"byType"`
+		ByLevel  []bucket `bson:"byLevel"`
+		ByMedal  []bucket `bson:"byMedal"`
+		ByStatus []bucket `bson:"byStatus"`
+		Series   []struct {
+			ID struct {
+				Period string `bson:"period"`
+				Status string `bson:"status"`
+			} `bson:"_id"`
+			Count int `bson:"count"`
+		} `bson:"series"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return err
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	facets := result[0]
 
+	add := func(target map[string]int, buckets []bucket) {
+		for _, b := range buckets {
+			target[b.ID] += b.Count
+		}
+	}
+	add(stats.ByType, facets.ByType)
+	add(stats.ByLevel, facets.ByLevel)
+	add(stats.ByMedal, facets.ByMedal)
+	add(stats.ByStatus, facets.ByStatus)
+
+	for _, b := range facets.ByStatus {
+		stats.Total += b.Count
+	}
+
+	// gabungkan status dalam periode yang sama, juga antar batch
+	for _, s := range facets.Series {
+		point, ok := series[s.ID.Period]
+		if !ok {
+			point = &model.StatisticsPoint{Period: s.ID.Period, ByStatus: map[string]int{}}
+			series[s.ID.Period] = point
+		}
+		point.Total += s.Count
+		point.ByStatus[s.ID.Status] += s.Count
+	}
+
+	return nil
+}
+
+// SumPointsByStudent menjumlahkan poin prestasi (ids) per studentId,
+// dengan filter tipe prestasi dan rentang createdAt
+func (r *AchievementMongoDB) SumPointsByStudent(ctx context.Context, ids []string, filter model.LeaderboardFilter) ([]model.StudentPoints, error) {
+	if len(ids) == 0 {
+		return []model.StudentPoints{}, nil
+	}
+
+	values := bson.A{}
+	for _, id := range ids {
+		values = append(values, id)
+		if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+			values = append(values, objID)
+		}
+	}
+
+	match := bson.M{"_id": bson.M{"$in": values}}
+	if filter.AchievementType != "" {
+		match["achievementType"] = filter.AchievementType
+	}
+	if createdAt := dateRange(filter.From, filter.To); createdAt != nil {
+		match["createdAt"] = createdAt
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$studentId",
+			"points":       bson.M{"$sum": "$points"},
+			"achievements": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var result []model.StudentPoints
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListOwners mengembalikan id prestasi → studentId (users.id) untuk semua dokumen
+func (r *AchievementMongoDB) ListOwners(ctx context.Context) (map[string]string, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "studentId": 1})
+
+	cursor, err := r.Collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	owners := map[string]string{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID        string `bson:"_id"`
+			StudentID string `bson:"studentId"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		owners[doc.ID] = doc.StudentID
+	}
+
+	return owners, cursor.Err()
+}
+
+// ApplyStatusSync menyalin status dari PostgreSQL ke dokumen prestasi.
+// Hanya diterapkan jika sync.Seq lebih baru dari statusSeq di dokumen, sehingga
+// pesan yang diulang atau datang terlambat tidak menimpa status yang lebih baru.
+func (r *AchievementMongoDB) ApplyStatusSync(ctx context.Context, id string, sync model.AchievementStatusSync) error {
+	filter := achievementIDFilter(id)
+	filter["$or"] = bson.A{
+		bson.M{"statusSeq": bson.M{"$exists": false}},
+		bson.M{"statusSeq": bson.M{"$lt": sync.Seq}},
+	}
+
+	// update pipeline: nilai dari request dibungkus $literal agar teks berawalan "$" tidak
+	// dibaca sebagai field path
+	set := bson.M{
+		"status":          bson.M{"$literal": sync.Status},
+		"statusSeq":       sync.Seq,
+		"statusUpdatedAt": sync.OccurredAt,
+	}
+	unset := bson.A{}
+
+	switch sync.Status {
+	case model.StatusVerified, model.StatusRejected:
+		set["verifiedAt"] = sync.OccurredAt
+		set["verifiedBy"] = bson.M{"$literal": sync.VerifiedBy}
+		set["rejectionNote"] = bson.M{"$literal": sync.RejectionNote}
+	default:
+		unset = append(unset, "verifiedAt", "verifiedBy", "rejectionNote")
+	}
+
+	// poin hanya ditimpa jika belum ada atau versinya lebih lama, sama seperti ApplyPointsSync;
+	// recalculation yang lebih baru tidak tertimpa status sync yang tertunda di outbox
+	if sync.Points != nil {
+		version := PointsVersion(sync.OccurredAt)
+		newer := bson.M{"$or": bson.A{
+			bson.M{"$eq": bson.A{bson.M{"$type": "$pointsVersion"}, "missing"}},
+			bson.M{"$lt": bson.A{"$pointsVersion", version}},
+		}}
+		set["points"] = bson.M{"$cond": bson.A{newer, *sync.Points, "$points"}}
+		set["pointsVersion"] = bson.M{"$cond": bson.A{newer, version, "$pointsVersion"}}
+	}
+
+	update := mongo.Pipeline{{{Key: "$set", Value: set}}}
+	if len(unset) > 0 {
+		update = append(update, bson.D{{Key: "$unset", Value: unset}})
+	}
+
+	// dokumen yang tidak cocok = sudah diterapkan atau sudah dihapus, keduanya dianggap selesai
+	_, err := r.Collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// ApplyPointsSync menyalin poin hasil recalculation, diabaikan jika versinya lebih lama
+func (r *AchievementMongoDB) ApplyPointsSync(ctx context.Context, id string, sync model.AchievementPointsSync) error {
+	filter := achievementIDFilter(id)
+	filter["$or"] = bson.A{
+		bson.M{"pointsVersion": bson.M{"$exists": false}},
+		bson.M{"pointsVersion": bson.M{"$lt": sync.Version}},
+	}
+
+	_, err := r.Collection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"points":        sync.Points,
+			"pointsVersion": sync.Version,
+		},
+	})
 	return err
 }
 
+// achievementIDFilter: prestasi yang dibuat lewat API memakai UUID string sebagai _id,
+// sedangkan data lama memakai ObjectID
+func achievementIDFilter(id string) bson.M {
+	if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+		return bson.M{"_id": bson.M{"$in": bson.A{id, objID}}}
+	}
+	return bson.M{"_id": id}
+}
@@ -7,19 +7,22 @@ import (
 )
 
 type ReportRepository interface {
-	GetStatics(ctx context.Context, filter StatisticsFilter) (*model.AchievementStatistics, error)
+	GetReferenceStatuses(ctx context.Context, filter StatisticsFilter) (map[string]string, error)
 	GetStudentReport(ctx context.Context, studentID string) (*model.StudentReport, error)
+	GetLeaderboardCandidates(ctx context.Context, programStudy string, academicYear string) ([]model.LeaderboardCandidate, error)
 	IsAdvisor(
 		ctx context.Context,
-		lecturerID string,
+		lecturerUserID string,
 		studentID string,
 	) (bool, error)
+	IsOwnStudent(ctx context.Context, userID string, studentID string) (bool, error)
 }
 
 type StaticsReport struct {
 	DB *sql.DB
 }
 
+// StatisticsFilter: keduanya berisi users.id dari JWT
 type StatisticsFilter struct {
 	StudentID  *string
 	LecturerID *string
@@ -29,48 +32,53 @@ func NewReportRepository(db *sql.DB) ReportRepository {
 	return &StaticsReport{DB: db}
 }
 
-func (r *StaticsReport) GetStatics(ctx context.Context, filter StatisticsFilter) (*model.AchievementStatistics, error) {
-	where := ""
+// GetReferenceStatuses mengembalikan mongo_achievement_id → status untuk prestasi yang
+// terlihat oleh filter, tanpa draft dan deleted seperti hitungan GetStudentReport.
+// Statistiknya dihitung di MongoDB (AchievementRepository.AggregateStatistics).
+func (r *StaticsReport) GetReferenceStatuses(ctx context.Context, filter StatisticsFilter) (map[string]string, error) {
+	where := "WHERE ar.status NOT IN ('draft', 'deleted')"
 	args := []interface{}{}
 
 	if filter.StudentID != nil {
-		where = "WHERE ar.student_id = $1"
+		where = `
+			JOIN students s ON s.id = ar.student_id
+			WHERE s.user_id = $1
+			  AND ar.status NOT IN ('draft', 'deleted')
+		`
 		args = append(args, *filter.StudentID)
 	}
 
 	if filter.LecturerID != nil {
 		where = `
 			JOIN students s ON s.id = ar.student_id
-			WHERE s.advisor_id = $1
+			JOIN lecturers l ON l.id = s.advisor_id
+			WHERE l.user_id = $1
+			  AND ar.status NOT IN ('draft', 'deleted')
 		`
 		args = append(args, *filter.LecturerID)
 	}
 
 	query := `
-		SELECT
-			COUNT(*) AS total,
-			COUNT(*) FILTER (WHERE ar.status = 'submitted') AS submitted,
-			COUNT(*) FILTER (WHERE ar.status = 'verified') AS verified,
-			COUNT(*) FILTER (WHERE ar.status = 'rejected') AS rejected
+		SELECT ar.mongo_achievement_id, ar.status
 		FROM achievement_references ar
 		` + where
 
-	row := r.DB.QueryRowContext(ctx, query, args...)
-
-	stats := new(model.AchievementStatistics)
-
-	err := row.Scan(
-		&stats.Total,
-		&stats.Submitted,
-		&stats.Verified,
-		&stats.Rejected,
-	)
-
+	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return stats, nil
+	statuses := map[string]string{}
+	for rows.Next() {
+		var id, status string
+		if err := rows.Scan(&id, &status); err != nil {
+			return nil, err
+		}
+		statuses[id] = status
+	}
+
+	return statuses, rows.Err()
 }
 
 func (r *StaticsReport) GetStudentReport(ctx context.Context, studentID string) (*model.StudentReport, error) {
@@ -82,6 +90,7 @@ func (r *StaticsReport) GetStudentReport(ctx context.Context, studentID string)
 			COUNT(*) FILTER (WHERE status = 'rejected') AS rejected
 		FROM achievement_references
 		WHERE student_id = $1
+		  AND status NOT IN ('draft', 'deleted')
 	`
 
 	report := &model.StudentReport{
@@ -103,19 +112,17 @@ func (r *StaticsReport) GetStudentReport(ctx context.Context, studentID string)
 		return nil, err
 	}
 
+	// Judul, tipe dan detail prestasi ada di MongoDB, digabungkan oleh ReportService
 	listQuery := `
 		SELECT
 			ar.mongo_achievement_id,
-			a.title,
-			a.achievement_type,
 			ar.status,
 			ar.submitted_at,
 			ar.verified_at
 		FROM achievement_references ar
-		JOIN achievements a
-		  ON a.id = ar.mongo_achievement_id
 		WHERE ar.student_id = $1
-		ORDER BY ar.submitted_at DESC
+		  AND ar.status NOT IN ('draft', 'deleted')
+		ORDER BY ar.submitted_at DESC NULLS LAST, ar.created_at DESC
 	`
 
 	rows, err := r.DB.QueryContext(ctx, listQuery, studentID)
@@ -128,8 +135,6 @@ func (r *StaticsReport) GetStudentReport(ctx context.Context, studentID string)
 		var item model.StudentAchievementItem
 		if err := rows.Scan(
 			&item.MongoAchievementID,
-			&item.Title,
-			&item.AchievementType,
 			&item.Status,
 			&item.SubmittedAt,
 			&item.VerifiedAt,
@@ -140,21 +145,68 @@ func (r *StaticsReport) GetStudentReport(ctx context.Context, studentID string)
 		report.Achievements = append(report.Achievements, item)
 	}
 
-	return report, nil
+	return report, rows.Err()
+}
+
+// GetLeaderboardCandidates mengembalikan prestasi verified beserta data mahasiswanya.
+// Filter kosong berarti semua program studi / angkatan.
+func (r *StaticsReport) GetLeaderboardCandidates(ctx context.Context, programStudy string, academicYear string) ([]model.LeaderboardCandidate, error) {
+	query := `
+		SELECT
+			ar.mongo_achievement_id,
+			s.id,
+			s.user_id,
+			u.full_name,
+			COALESCE(s.program_study, ''),
+			COALESCE(s.academic_year, '')
+		FROM achievement_references ar
+		JOIN students s ON s.id = ar.student_id
+		JOIN users u ON u.id = s.user_id
+		WHERE ar.status = 'verified'
+		  AND ($1 = '' OR s.program_study = $1)
+		  AND ($2 = '' OR s.academic_year = $2)
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, programStudy, academicYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []model.LeaderboardCandidate
+	for rows.Next() {
+		var c model.LeaderboardCandidate
+		if err := rows.Scan(
+			&c.MongoAchievementID,
+			&c.StudentID,
+			&c.UserID,
+			&c.FullName,
+			&c.ProgramStudy,
+			&c.AcademicYear,
+		); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, rows.Err()
 }
 
+// IsAdvisor: lecturerUserID adalah users.id dari JWT, studentID adalah students.id.
+// advisor_id berisi lecturers.id sehingga harus di-join lewat l.user_id
 func (r *StaticsReport) IsAdvisor(
 	ctx context.Context,
-	lecturerID string,
+	lecturerUserID string,
 	studentID string,
 ) (bool, error) {
 
 	query := `
 		SELECT EXISTS (
 			SELECT 1
-			FROM students
-			WHERE id = $1
-			  AND advisor_id = $2
+			FROM students s
+			JOIN lecturers l ON l.id = s.advisor_id
+			WHERE s.id = $1
+			  AND l.user_id = $2
 		)
 	`
 
@@ -163,8 +215,25 @@ func (r *StaticsReport) IsAdvisor(
 		ctx,
 		query,
 		studentID,
-		lecturerID,
+		lecturerUserID,
 	).Scan(&exists)
 
 	return exists, err
 }
+
+// IsOwnStudent memastikan students.id milik users.id dari JWT
+func (r *StaticsReport) IsOwnStudent(ctx context.Context, userID string, studentID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM students s
+			WHERE s.id = $1
+			  AND s.user_id = $2
+		)
+	`
+
+	var exists bool
+	err := r.DB.QueryRowContext(ctx, query, studentID, userID).Scan(&exists)
+
+	return exists, err
+}
@@ -0,0 +1,460 @@
+package repository
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ErrStatusChanged: status sudah diubah request lain di antara Guard dan UpdateStatus
+var ErrStatusChanged = Conflict("achievement status changed, please retry")
+
+// ErrNotAdvisor: verify / reject hanya oleh dosen wali pemilik prestasi
+var ErrNotAdvisor = Forbidden("achievement does not belong to your advisee")
+
+// ErrReviewClaimed: verify / reject ditolak karena claim dosen lain masih berlaku
+var ErrReviewClaimed = Conflict("achievement is claimed by another lecturer")
+
+// AchievementReferenceRepository adalah satu-satunya tempat status prestasi ditulis.
+// Aturan transisinya ada di service.AchievementLifecycle. Setiap perubahan status
+// dicatat ke achievement_status_events dan achievement_outbox dalam transaksi yang sama.
+type AchievementReferenceRepository interface {
+	FindByMongoID(ctx context.Context, mongoAchievementID string) (*model.AchievementReference, error)
+	Create(ctx context.Context, ref *model.AchievementReference, actorID string) error
+	UpdateStatus(ctx context.Context, t model.StatusTransition) error
+	ApplyTransitions(ctx context.Context, transitions []model.StatusTransition) ([]error, error)
+	ListLatest(ctx context.Context) ([]model.AchievementReference, error)
+	UpdatePoints(ctx context.Context, mongoAchievementID string, points int) (bool, error)
+	SetPublic(ctx context.Context, mongoAchievementID string, public bool) error
+}
+
+type achievementReferencePostgres struct {
+	db *sql.DB
+}
+
+func NewAchievementReferenceRepository(db *sql.DB) AchievementReferenceRepository {
+	return &achievementReferencePostgres{db: db}
+}
+
+func (r *achievementReferencePostgres) FindByMongoID(ctx context.Context, mongoAchievementID string) (*model.AchievementReference, error) {
+	query := `
+		SELECT
+			id,
+			student_id,
+			mongo_achievement_id,
+			status,
+			submitted_at,
+			verified_at,
+			verified_by,
+			rejection_reason,
+			is_public,
+			created_at,
+			updated_at
+		FROM achievement_references
+		WHERE mongo_achievement_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var ref model.AchievementReference
+	err := r.db.QueryRowContext(ctx, query, mongoAchievementID).Scan(
+		&ref.ID,
+		&ref.StudentID,
+		&ref.MongoAchievementID,
+		&ref.Status,
+		&ref.SubmittedAt,
+		&ref.VerifiedAt,
+		&ref.VerifiedBy,
+		&ref.RejectionNote,
+		&ref.IsPublic,
+		&ref.CreatedAt,
+		&ref.UpdatedAt,
+	)
+	if err != nil {
+		return nil, notFound(err, "achievement reference not found")
+	}
+
+	return &ref, nil
+}
+
+// ListLatest mengembalikan reference terbaru untuk setiap prestasi, termasuk yang sudah deleted
+func (r *achievementReferencePostgres) ListLatest(ctx context.Context) ([]model.AchievementReference, error) {
+	query := `
+		SELECT DISTINCT ON (mongo_achievement_id)
+			id,
+			student_id,
+			mongo_achievement_id,
+			status,
+			submitted_at,
+			verified_at,
+			verified_by,
+			rejection_reason,
+			is_public,
+			created_at,
+			updated_at
+		FROM achievement_references
+		ORDER BY mongo_achievement_id, created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []model.AchievementReference
+	for rows.Next() {
+		var ref model.AchievementReference
+		if err := rows.Scan(
+			&ref.ID,
+			&ref.StudentID,
+			&ref.MongoAchievementID,
+			&ref.Status,
+			&ref.SubmittedAt,
+			&ref.VerifiedAt,
+			&ref.VerifiedBy,
+			&ref.RejectionNote,
+			&ref.IsPublic,
+			&ref.CreatedAt,
+			&ref.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
+
+func (r *achievementReferencePostgres) Create(ctx context.Context, ref *model.AchievementReference, actorID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO achievement_references
+		(id, student_id, mongo_achievement_id, status, submitted_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		ref.ID,
+		ref.StudentID,
+		ref.MongoAchievementID,
+		ref.Status,
+		ref.SubmittedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = recordStatusChange(ctx, tx, ref.ID, model.StatusTransition{
+		MongoAchievementID: ref.MongoAchievementID,
+		To:                 ref.Status,
+		ActorID:            actorID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateStatus hanya berhasil jika status di database masih sama dengan t.From,
+// sehingga dua transisi yang berjalan bersamaan tidak saling menimpa.
+// ErrStatusChanged dikembalikan jika status sudah berubah.
+func (r *achievementReferencePostgres) UpdateStatus(ctx context.Context, t model.StatusTransition) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := applyTransition(ctx, tx, t); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ApplyTransitions menerapkan banyak transisi dalam satu transaksi. Setiap item memakai
+// savepoint sendiri: item yang gagal (ErrStatusChanged, dll) di-rollback tanpa membatalkan
+// item lain, hasilnya di results[i]. err hanya untuk kegagalan transaksi; tidak ada yang tersimpan.
+func (r *achievementReferencePostgres) ApplyTransitions(ctx context.Context, transitions []model.StatusTransition) ([]error, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]error, len(transitions))
+	for i, t := range transitions {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT transition"); err != nil {
+			return nil, err
+		}
+
+		results[i] = applyTransition(ctx, tx, t)
+
+		release := "RELEASE SAVEPOINT transition"
+		if results[i] != nil {
+			release = "ROLLBACK TO SAVEPOINT transition"
+		}
+		if _, err := tx.ExecContext(ctx, release); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, tx.Commit()
+}
+
+func applyTransition(ctx context.Context, tx *sql.Tx, t model.StatusTransition) error {
+	query, args := statusUpdateQuery(t)
+
+	var referenceID string
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&referenceID)
+	if err == sql.ErrNoRows {
+		return transitionRejected(ctx, tx, t)
+	}
+	if err != nil {
+		return err
+	}
+
+	return recordStatusChange(ctx, tx, referenceID, t)
+}
+
+// transitionRejected menjelaskan kenapa UPDATE transisi tidak mengenai baris apa pun:
+// untuk verify / reject bisa karena actor bukan dosen wali atau claim dosen lain masih
+// berlaku, selain itu status sudah berubah
+func transitionRejected(ctx context.Context, tx *sql.Tx, t model.StatusTransition) error {
+	if t.To != model.StatusVerified && t.To != model.StatusRejected {
+		return ErrStatusChanged
+	}
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1
+				FROM achievement_references ar
+				JOIN students s ON s.id = ar.student_id
+				JOIN lecturers l ON l.id = s.advisor_id
+				WHERE ar.mongo_achievement_id = $1
+				  AND l.user_id = $2
+			),
+			EXISTS (
+				SELECT 1
+				FROM achievement_references
+				WHERE mongo_achievement_id = $1
+				  AND status = 'submitted'
+				  AND claimed_by <> $2
+				  AND claimed_at >= $3
+			)
+	`
+
+	var advisor, claimed bool
+	err := tx.QueryRowContext(ctx, query, t.MongoAchievementID, t.ActorID, t.ClaimedBefore).Scan(&advisor, &claimed)
+	if err != nil {
+		return err
+	}
+	if !advisor {
+		return ErrNotAdvisor
+	}
+	if claimed {
+		return ErrReviewClaimed
+	}
+
+	return ErrStatusChanged
+}
+
+// UpdatePoints menyimpan poin hasil recalculation untuk prestasi verified dan
+// mengirim pesan outbox ke MongoDB. false berarti poin tidak berubah / bukan verified.
+// Versi poin diambil dari waktu transaksi, sama seperti poin di status sync.
+func (r *achievementReferencePostgres) UpdatePoints(ctx context.Context, mongoAchievementID string, points int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE achievement_references
+		SET points = $1,
+		    updated_at = NOW()
+		WHERE mongo_achievement_id = $2
+		  AND status = 'verified'
+		  AND points IS DISTINCT FROM $1
+		RETURNING id, NOW()
+	`
+
+	var (
+		referenceID string
+		updatedAt   time.Time
+	)
+	err = tx.QueryRowContext(ctx, query, points, mongoAchievementID).Scan(&referenceID, &updatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	version := PointsVersion(updatedAt)
+	err = enqueueOutbox(ctx, tx, model.OutboxMessage{
+		IdempotencyKey:     fmt.Sprintf("points:%s:%d", referenceID, version),
+		MongoAchievementID: mongoAchievementID,
+		EventType:          model.OutboxPointsSync,
+	}, model.AchievementPointsSync{Points: points, Version: version})
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// SetPublic mengubah flag portfolio publik. Hanya prestasi verified yang bisa diubah,
+// selain itu ErrStatusChanged (status berubah setelah dicek service).
+func (r *achievementReferencePostgres) SetPublic(ctx context.Context, mongoAchievementID string, public bool) error {
+	query := `
+		UPDATE achievement_references
+		SET is_public = $1,
+		    updated_at = NOW()
+		WHERE mongo_achievement_id = $2
+		  AND status = 'verified'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, public, mongoAchievementID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrStatusChanged
+	}
+
+	return nil
+}
+
+// PointsVersion: versi poin di MongoDB (pointsVersion) dari waktu transaksi PostgreSQL.
+// Dipakai points sync (UpdatePoints) dan status sync (OccurredAt) agar keduanya sebanding.
+func PointsVersion(at time.Time) int64 {
+	return at.UnixNano()
+}
+
+// recordStatusChange mencatat event audit dan pesan outbox untuk sinkronisasi status ke MongoDB.
+// Dipanggil di dalam transaksi yang sama dengan perubahan achievement_references.
+func recordStatusChange(ctx context.Context, tx *sql.Tx, referenceID string, t model.StatusTransition) error {
+	eventID, occurredAt, err := insertStatusEvent(ctx, tx, referenceID, t)
+	if err != nil {
+		return err
+	}
+
+	sync := model.AchievementStatusSync{
+		Seq:        eventID,
+		Status:     t.To,
+		OccurredAt: occurredAt,
+	}
+	if t.To == model.StatusVerified || t.To == model.StatusRejected {
+		sync.VerifiedBy = &t.ActorID
+		sync.RejectionNote = t.Note
+		sync.Points = t.Points
+	}
+
+	return enqueueOutbox(ctx, tx, model.OutboxMessage{
+		IdempotencyKey:     fmt.Sprintf("status-event:%d", eventID),
+		MongoAchievementID: t.MongoAchievementID,
+		EventType:          model.OutboxStatusSync,
+	}, sync)
+}
+
+// insertStatusEvent menambah satu baris ke log audit. From kosong berarti reference baru dibuat.
+func insertStatusEvent(ctx context.Context, tx *sql.Tx, referenceID string, t model.StatusTransition) (int64, time.Time, error) {
+	query := `
+		INSERT INTO achievement_status_events
+		(reference_id, mongo_achievement_id, actor_id, from_status, to_status, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	var (
+		id        int64
+		createdAt time.Time
+	)
+
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		referenceID,
+		t.MongoAchievementID,
+		nullString(t.ActorID),
+		nullString(t.From),
+		t.To,
+		t.Note,
+	).Scan(&id, &createdAt)
+
+	return id, createdAt, err
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func statusUpdateQuery(t model.StatusTransition) (string, []interface{}) {
+	switch t.To {
+	case model.StatusSubmitted:
+		// submit pertama maupun resubmit setelah ditolak
+		return `
+			UPDATE achievement_references
+			SET status = $1,
+			    submitted_at = NOW(),
+			    verified_at = NULL,
+			    verified_by = NULL,
+			    rejection_reason = NULL,
+			    points = NULL,
+			    updated_at = NOW()
+			WHERE mongo_achievement_id = $2
+			  AND status = $3
+			RETURNING id
+		`, []interface{}{t.To, t.MongoAchievementID, t.From}
+
+	case model.StatusVerified, model.StatusRejected:
+		// dosen wali dan claim dicek di UPDATE yang sama (aturan yang sama dengan ClaimReview),
+		// bukan sebelum transaksi; claim dilepas bersamaan dengan keputusan
+		return `
+			UPDATE achievement_references ar
+			SET status = $1,
+			    verified_at = NOW(),
+			    verified_by = $2,
+			    rejection_reason = $3,
+			    points = $6,
+			    claimed_by = NULL,
+			    claimed_at = NULL,
+			    updated_at = NOW()
+			FROM students s
+			JOIN lecturers l ON l.id = s.advisor_id
+			WHERE ar.student_id = s.id
+			  AND l.user_id = $2
+			  AND ar.mongo_achievement_id = $4
+			  AND ar.status = $5
+			  AND (ar.claimed_by IS NULL OR ar.claimed_by = $2 OR ar.claimed_at < $7)
+			RETURNING ar.id
+		`, []interface{}{t.To, t.ActorID, t.Note, t.MongoAchievementID, t.From, t.Points, t.ClaimedBefore}
+	}
+
+	return `
+		UPDATE achievement_references
+		SET status = $1,
+		    updated_at = NOW()
+		WHERE mongo_achievement_id = $2
+		  AND status = $3
+		RETURNING id
+	`, []interface{}{t.To, t.MongoAchievementID, t.From}
+}
@@ -10,6 +10,7 @@ type AuthRepository interface {
 	FindByEmail(Email string) (*model.User, error)
 	GetRoleByUserID(userID string) (string, error)
 	GetProfile(id string) (*model.User, error)
+	GetPermissionsByRole(roleName string) ([]string, error)
 }
 
 type AuthPostGres struct {
@@ -88,3 +89,35 @@ func (r *AuthPostGres) GetProfile(id string) (*model.User, error) {
 
 	return user, nil
 }
+
+func (r *AuthPostGres) GetPermissionsByRole(roleName string) ([]string, error) {
+	query := `
+		SELECT p.name
+		FROM permissions p
+		JOIN role_permissions rp ON rp.permission_id = p.id
+		JOIN roles ro ON ro.id = rp.role_id
+		WHERE ro.name = $1
+		ORDER BY p.name;
+	`
+
+	rows, err := r.db.Query(query, roleName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// ErrorKind mengelompokkan error domain, dipetakan ke status HTTP oleh middleware.ErrorHandler
+type ErrorKind int
+
+const (
+	KindNotFound  ErrorKind = iota + 1 // 404
+	KindConflict                       // 409
+	KindForbidden                      // 403
+	KindInvalid                        // 400, input valid secara format tapi ditolak data
+)
+
+// DomainError adalah error repository yang pesannya aman dikirim ke client
+type DomainError struct {
+	Kind    ErrorKind
+	Message string
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+// Is membuat errors.Is(err, ErrNotFound) berlaku untuk semua error ber-Kind sama
+func (e *DomainError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Kind == KindNotFound
+	case ErrConflict:
+		return e.Kind == KindConflict
+	case ErrForbidden:
+		return e.Kind == KindForbidden
+	case ErrInvalid:
+		return e.Kind == KindInvalid
+	}
+	return false
+}
+
+// Error generik per Kind, dipakai dengan errors.Is
+var (
+	ErrNotFound  = &DomainError{Kind: KindNotFound, Message: "resource not found"}
+	ErrConflict  = &DomainError{Kind: KindConflict, Message: "resource conflict"}
+	ErrForbidden = &DomainError{Kind: KindForbidden, Message: "access denied"}
+	ErrInvalid   = &DomainError{Kind: KindInvalid, Message: "invalid request"}
+)
+
+func NotFound(message string) error  { return &DomainError{Kind: KindNotFound, Message: message} }
+func Conflict(message string) error  { return &DomainError{Kind: KindConflict, Message: message} }
+func Forbidden(message string) error { return &DomainError{Kind: KindForbidden, Message: message} }
+func Invalid(message string) error   { return &DomainError{Kind: KindInvalid, Message: message} }
+
+// notFound mengganti sql.ErrNoRows dengan error domain, error lain diteruskan apa adanya
+func notFound(err error, message string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound(message)
+	}
+	return err
+}
@@ -4,14 +4,22 @@ import (
 	model "PROJECTUAS_BE/app/Model"
 	"context"
 	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+// ErrClaimConflict: prestasi tidak ada di antrean dosen atau sedang di-claim dosen lain
+var ErrClaimConflict = Conflict("achievement is not awaiting your review or is claimed by another lecturer")
+
 type LecturesRepository interface {
-	Verify(ctx context.Context, mongoAchievementID string, status string, verifiedBy string, reason *string) error
-	Reject(ctx context.Context, mongoAchievementID string, reason string, rejectedBy string) error
 	GetHistory(ctx context.Context, mongoAchievementID string) ([]*model.AchievementHistory, error)
-	GetallLectures(ctx context.Context) ([]*model.LecturerResponse, error)
+	GetallLectures(ctx context.Context, filter model.LecturerFilter, q model.ListQuery) ([]*model.LecturerResponse, model.PageInfo, error)
 	Getadvisees(ctx context.Context, lecturerID string) ([]*model.AdviseeResponse, error)
+	GetReviewQueue(ctx context.Context, filter model.ReviewQueueFilter, q model.ListQuery) ([]model.ReviewQueueItem, model.PageInfo, error)
+	FindQueueItems(ctx context.Context, lecturerUserID string, mongoAchievementIDs []string) (map[string]model.ReviewQueueItem, error)
+	ClaimReview(ctx context.Context, mongoAchievementID string, lecturerUserID string, staleBefore time.Time) (time.Time, error)
+	ReleaseReview(ctx context.Context, mongoAchievementID string, lecturerUserID string) error
 }
 
 type lecturePostGres struct {
@@ -22,83 +30,26 @@ func NewLecturesRepository(db *sql.DB) LecturesRepository {
 	return &lecturePostGres{db}
 }
 
-func (r *lecturePostGres) Verify(ctx context.Context, mongoAchievementID string, status string, verifiedBy string, reason *string) error {
-	query := `
-		UPDATE achievement_references
-		SET status = $1,
-		    verified_at = NOW(),
-		    verified_by = $2,
-		    rejection_reason = $3
-		WHERE mongo_achievement_id = $4
-		  AND status = 'submitted'
-	`
-
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		status,
-		verifiedBy,
-		reason,
-		mongoAchievementID,
-	)
-
-	if err != nil {
-		return err
-	}
-
-	rows, _ := result.RowsAffected()
-	if rows == 0 {
-		return sql.ErrNoRows
-	}
-
-	return nil
-}
-
-func (r *lecturePostGres) Reject(ctx context.Context, mongoAchievementID string, reason string, rejectedBy string) error {
-	query := `
-		UPDATE achievement_references
-		SET status = 'rejected',
-		    verified_at = NOW(),
-		    verified_by = $1,
-		    rejection_reason = $2
-		WHERE mongo_achievement_id = $3
-		  AND status = 'submitted'
-	`
-
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		rejectedBy,
-		reason,
-		mongoAchievementID,
-	)
-
-	if err != nil {
-		return err
-	}
-
-	rows, _ := result.RowsAffected()
-	if rows == 0 {
-		return sql.ErrNoRows
-	}
-
-	return nil
-}
-
+// GetHistory membaca timeline perubahan status dari log audit, urut dari yang paling lama
 func (r *lecturePostGres) GetHistory(ctx context.Context, mongoAchievementID string) ([]*model.AchievementHistory, error) {
 	query := `
 		SELECT
-			id,
-			mongo_achievement_id,
-			student_id,
-			status,
-			submitted_at,
-			verified_at,
-			verified_by,
-			rejection_reason
-		FROM achievement_references
-		WHERE mongo_achievement_id = $1
-		ORDER BY submitted_at ASC
+			e.id,
+			e.mongo_achievement_id,
+			ar.student_id,
+			s.user_id,
+			e.from_status,
+			e.to_status,
+			e.actor_id,
+			u.full_name,
+			e.note,
+			e.created_at
+		FROM achievement_status_events e
+		JOIN achievement_references ar ON ar.id = e.reference_id
+		JOIN students s ON s.id = ar.student_id
+		LEFT JOIN users u ON u.id = e.actor_id
+		WHERE e.mongo_achievement_id = $1
+		ORDER BY e.id ASC
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, mongoAchievementID)
@@ -115,11 +66,13 @@ func (r *lecturePostGres) GetHistory(ctx context.Context, mongoAchievementID str
 			&h.ID,
 			&h.MongoAchievementID,
 			&h.StudentID,
-			&h.Status,
-			&h.SubmittedAt,
-			&h.VerifiedAt,
-			&h.VerifiedBy,
-			&h.RejectionReason,
+			&h.StudentUserID,
+			&h.FromStatus,
+			&h.ToStatus,
+			&h.ActorID,
+			&h.ActorName,
+			&h.Note,
+			&h.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -130,44 +83,51 @@ func (r *lecturePostGres) GetHistory(ctx context.Context, mongoAchievementID str
 
 }
 
-func (r *lecturePostGres) GetallLectures(ctx context.Context) ([]*model.LecturerResponse, error) {
-	query := `
-		SELECT
+// GetallLectures: sort full_name | username | department | created_at
+func (r *lecturePostGres) GetallLectures(ctx context.Context, filter model.LecturerFilter, q model.ListQuery) ([]*model.LecturerResponse, model.PageInfo, error) {
+	list := pgList{
+		Columns: `
 			l.id,
 			l.user_id,
 			l.department,
 			u.username,
 			u.email,
-			u.full_name
-		FROM lecturers l
-		JOIN users u ON u.id = l.user_id
-		ORDER BY u.full_name ASC
-	`
-
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
+			u.full_name`,
+		From:     "FROM lecturers l JOIN users u ON u.id = l.user_id",
+		IDColumn: "l.id",
+		Sorts: map[string]string{
+			"full_name":  "u.full_name",
+			"username":   "u.username",
+			"department": "l.department",
+			"created_at": "l.created_at",
+		},
+		DefaultSort: "full_name",
 	}
-	defer rows.Close()
 
-	var lecturers []*model.LecturerResponse
+	if filter.Department != "" {
+		list.filter("l.department = ?", filter.Department)
+	}
 
-	for rows.Next() {
-		var l model.LecturerResponse
-		if err := rows.Scan(
+	lecturers, info, err := listPage(ctx, r.db, list, q, func(l *model.LecturerResponse) []interface{} {
+		return []interface{}{
 			&l.ID,
 			&l.UserID,
 			&l.Department,
 			&l.Username,
 			&l.Email,
 			&l.FullName,
-		); err != nil {
-			return nil, err
 		}
-		lecturers = append(lecturers, &l)
+	})
+	if err != nil {
+		return nil, info, err
 	}
 
-	return lecturers, nil
+	result := make([]*model.LecturerResponse, len(lecturers))
+	for i := range lecturers {
+		result[i] = &lecturers[i]
+	}
+
+	return result, info, nil
 }
 
 func (r *lecturePostGres) Getadvisees(ctx context.Context, lecturerID string) ([]*model.AdviseeResponse, error) {
@@ -207,3 +167,139 @@ func (r *lecturePostGres) Getadvisees(ctx context.Context, lecturerID string) ([
 
 	return result, nil
 }
+
+// reviewQueueList: prestasi submitted milik mahasiswa bimbingan dosen (lecturerUserID = users.id dosen)
+func reviewQueueList(lecturerUserID string) pgList {
+	list := pgList{
+		Columns: `
+			ar.id,
+			ar.mongo_achievement_id,
+			ar.student_id,
+			s.user_id,
+			u.full_name,
+			u.username,
+			ar.submitted_at,
+			ar.claimed_by,
+			ar.claimed_at`,
+		From: `FROM achievement_references ar
+			JOIN students s ON s.id = ar.student_id
+			JOIN lecturers l ON l.id = s.advisor_id
+			JOIN users u ON u.id = s.user_id`,
+		IDColumn: "ar.id",
+		Sorts: map[string]string{
+			"submitted_at": "ar.submitted_at",
+		},
+		DefaultSort: "submitted_at",
+	}
+
+	list.filter("l.user_id = ?", lecturerUserID)
+	list.filter("ar.status = ?", model.StatusSubmitted)
+
+	return list
+}
+
+func reviewQueueFields(item *model.ReviewQueueItem) []interface{} {
+	return []interface{}{
+		&item.ReferenceID,
+		&item.MongoAchievementID,
+		&item.StudentID,
+		&item.StudentUserID,
+		&item.StudentName,
+		&item.StudentUsername,
+		&item.SubmittedAt,
+		&item.ClaimedBy,
+		&item.ClaimedAt,
+	}
+}
+
+// GetReviewQueue: sort submitted_at (default, yang paling lama menunggu lebih dulu)
+func (r *lecturePostGres) GetReviewQueue(ctx context.Context, filter model.ReviewQueueFilter, q model.ListQuery) ([]model.ReviewQueueItem, model.PageInfo, error) {
+	list := reviewQueueList(filter.LecturerUserID)
+
+	if filter.OverdueBefore != nil {
+		list.filter("ar.submitted_at < ?", *filter.OverdueBefore)
+	}
+
+	return listPage(ctx, r.db, list, q, reviewQueueFields)
+}
+
+// FindQueueItems mengambil item antrean dosen untuk id yang diminta, key = mongo_achievement_id.
+// Id yang bukan milik mahasiswa bimbingannya atau tidak berstatus submitted tidak ada di hasil.
+func (r *lecturePostGres) FindQueueItems(ctx context.Context, lecturerUserID string, mongoAchievementIDs []string) (map[string]model.ReviewQueueItem, error) {
+	list := reviewQueueList(lecturerUserID)
+	list.filter("ar.mongo_achievement_id = ANY(?)", pq.Array(mongoAchievementIDs))
+
+	rows, err := r.db.QueryContext(ctx, "SELECT "+list.Columns+" "+list.From+list.whereSQL(), list.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := map[string]model.ReviewQueueItem{}
+	for rows.Next() {
+		var item model.ReviewQueueItem
+		if err := rows.Scan(reviewQueueFields(&item)...); err != nil {
+			return nil, err
+		}
+		items[item.MongoAchievementID] = item
+	}
+
+	return items, rows.Err()
+}
+
+// ClaimReview menandai prestasi sedang direview oleh dosen. Claim dosen lain yang lebih lama
+// dari staleBefore dianggap kedaluwarsa dan bisa diambil alih; claim milik sendiri diperpanjang.
+func (r *lecturePostGres) ClaimReview(ctx context.Context, mongoAchievementID string, lecturerUserID string, staleBefore time.Time) (time.Time, error) {
+	query := `
+		UPDATE achievement_references ar
+		SET claimed_by = $2,
+		    claimed_at = NOW()
+		FROM students s
+		JOIN lecturers l ON l.id = s.advisor_id
+		WHERE ar.student_id = s.id
+		  AND l.user_id = $2
+		  AND ar.mongo_achievement_id = $1
+		  AND ar.status = 'submitted'
+		  AND (ar.claimed_by IS NULL OR ar.claimed_by = $2 OR ar.claimed_at < $3)
+		RETURNING ar.claimed_at
+	`
+
+	var claimedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, mongoAchievementID, lecturerUserID, staleBefore).Scan(&claimedAt)
+	if err == sql.ErrNoRows {
+		return claimedAt, ErrClaimConflict
+	}
+
+	return claimedAt, err
+}
+
+// ReleaseReview melepas claim milik dosen sendiri, dengan aturan dosen wali yang sama seperti ClaimReview
+func (r *lecturePostGres) ReleaseReview(ctx context.Context, mongoAchievementID string, lecturerUserID string) error {
+	query := `
+		UPDATE achievement_references ar
+		SET claimed_by = NULL,
+		    claimed_at = NULL
+		FROM students s
+		JOIN lecturers l ON l.id = s.advisor_id
+		WHERE ar.student_id = s.id
+		  AND l.user_id = $2
+		  AND ar.mongo_achievement_id = $1
+		  AND ar.claimed_by = $2
+		  AND ar.status = 'submitted'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, mongoAchievementID, lecturerUserID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return NotFound("you have no active claim on this achievement")
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// OutboxRepository membaca achievement_outbox untuk worker.
+// Pesan ditulis oleh repository lain lewat enqueueOutbox di dalam transaksinya sendiri.
+type OutboxRepository interface {
+	ClaimPending(ctx context.Context, limit int, lease time.Duration, maxAttempts int) ([]model.OutboxMessage, error)
+	MarkProcessed(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string, retryAt time.Time) error
+}
+
+type outboxPostgres struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) OutboxRepository {
+	return &outboxPostgres{db: db}
+}
+
+// ClaimPending mengambil pesan yang belum diproses dan menyewanya selama lease,
+// sehingga beberapa worker tidak memproses pesan yang sama secara bersamaan.
+// Pesan yang lease-nya habis (worker crash) akan diambil lagi.
+func (r *outboxPostgres) ClaimPending(ctx context.Context, limit int, lease time.Duration, maxAttempts int) ([]model.OutboxMessage, error) {
+	query := `
+		UPDATE achievement_outbox
+		SET attempts = attempts + 1,
+		    next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT id
+			FROM achievement_outbox
+			WHERE processed_at IS NULL
+			  AND next_attempt_at <= NOW()
+			  AND attempts < $3
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, idempotency_key, mongo_achievement_id, event_type, payload, attempts, last_error, created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, int(lease.Seconds()), maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []model.OutboxMessage
+	for rows.Next() {
+		var m model.OutboxMessage
+		var payload []byte
+		if err := rows.Scan(
+			&m.ID,
+			&m.IdempotencyKey,
+			&m.MongoAchievementID,
+			&m.EventType,
+			&payload,
+			&m.Attempts,
+			&m.LastError,
+			&m.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		m.Payload = payload
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err()
+}
+
+func (r *outboxPostgres) MarkProcessed(ctx context.Context, id int64) error {
+	query := `
+		UPDATE achievement_outbox
+		SET processed_at = NOW(),
+		    last_error = NULL
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *outboxPostgres) MarkFailed(ctx context.Context, id int64, reason string, retryAt time.Time) error {
+	query := `
+		UPDATE achievement_outbox
+		SET last_error = $2,
+		    next_attempt_at = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, reason, retryAt)
+	return err
+}
+
+// enqueueOutbox menulis pesan di dalam transaksi pemanggil.
+// Idempotency key yang sama tidak akan ditulis dua kali.
+func enqueueOutbox(ctx context.Context, tx *sql.Tx, m model.OutboxMessage, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO achievement_outbox
+		(idempotency_key, mongo_achievement_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`
+
+	_, err = tx.ExecContext(ctx, query, m.IdempotencyKey, m.MongoAchievementID, m.EventType, body)
+	return err
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidSort = Invalid("unsupported sort field")
+
+// pgList menyusun query list PostgreSQL: filter, COUNT untuk total, sorting yang di-whitelist,
+// dan paginasi offset atau keyset. Keyset membandingkan (kolom sort, id) dengan nilai dari cursor;
+// nilai disimpan sebagai teks dan di-cast kembali oleh PostgreSQL sesuai tipe kolomnya.
+type pgList struct {
+	Columns     string            // kolom SELECT milik item
+	From        string            // FROM ... JOIN ...
+	IDColumn    string            // tie-breaker, harus unik
+	Sorts       map[string]string // nama di query string → kolom
+	DefaultSort string
+
+	where []string
+	args  []interface{}
+}
+
+// filter menambahkan kondisi WHERE, setiap "?" diganti placeholder $n
+func (l *pgList) filter(cond string, args ...interface{}) {
+	for _, arg := range args {
+		l.args = append(l.args, arg)
+		cond = strings.Replace(cond, "?", fmt.Sprintf("$%d", len(l.args)), 1)
+	}
+	l.where = append(l.where, cond)
+}
+
+func (l *pgList) whereSQL() string {
+	if len(l.where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(l.where, " AND ")
+}
+
+// listPage menjalankan query list. fields mengembalikan pointer tujuan Scan untuk satu item.
+func listPage[T any](ctx context.Context, db *sql.DB, l pgList, q model.ListQuery, fields func(*T) []interface{}) ([]T, model.PageInfo, error) {
+	info := model.PageInfo{Limit: q.Limit}
+	if q.Cursor == "" {
+		info.Page = q.Page
+	}
+
+	sortName := q.Sort
+	if sortName == "" {
+		sortName = l.DefaultSort
+	}
+	column, ok := l.Sorts[sortName]
+	if !ok {
+		return nil, info, ErrInvalidSort
+	}
+
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) "+l.From+l.whereSQL(), l.args...).Scan(&info.Total); err != nil {
+		return nil, info, err
+	}
+
+	direction, op := "ASC", ">"
+	if q.Desc {
+		direction, op = "DESC", "<"
+	}
+
+	if q.Cursor != "" {
+		cursor, err := model.DecodeCursor(q.Cursor)
+		if err != nil {
+			return nil, info, Invalid(err.Error())
+		}
+		l.filter(fmt.Sprintf("(%s, %s) %s (?, ?)", column, l.IDColumn, op), cursor.Value, cursor.ID)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %s, %s::text, %s::text %s%s ORDER BY %s %s, %s %s LIMIT %d OFFSET %d",
+		l.Columns, column, l.IDColumn, l.From, l.whereSQL(),
+		column, direction, l.IDColumn, direction,
+		q.Limit+1, q.Offset(),
+	)
+
+	rows, err := db.QueryContext(ctx, query, l.args...)
+	if err != nil {
+		return nil, info, err
+	}
+	defer rows.Close()
+
+	items := []T{}
+	var lastValue, lastID string
+
+	for rows.Next() {
+		var item T
+		var sortValue, id string
+		if err := rows.Scan(append(fields(&item), &sortValue, &id)...); err != nil {
+			return nil, info, err
+		}
+
+		// baris ke limit+1 hanya penanda masih ada halaman berikutnya
+		if len(items) == q.Limit {
+			info.HasMore = true
+			break
+		}
+
+		items = append(items, item)
+		lastValue, lastID = sortValue, id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, info, err
+	}
+
+	if info.HasMore {
+		info.NextCursor = model.EncodeCursor(lastValue, lastID)
+	}
+
+	return items, info, nil
+}
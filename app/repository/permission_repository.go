@@ -0,0 +1,120 @@
+package repository
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+var ErrRoleOrPermissionNotFound = NotFound("role or permission not found")
+
+type PermissionRepository interface {
+	GetAllPermissions(ctx context.Context) ([]model.Permission, error)
+	GetPermissionsByRoleID(ctx context.Context, roleID string) ([]model.Permission, error)
+	Grant(ctx context.Context, roleID string, permissionID string) error
+	Revoke(ctx context.Context, roleID string, permissionID string) error
+}
+
+type permissionPostgres struct {
+	db *sql.DB
+}
+
+func NewPermissionRepository(db *sql.DB) PermissionRepository {
+	return &permissionPostgres{db: db}
+}
+
+func (r *permissionPostgres) GetAllPermissions(ctx context.Context) ([]model.Permission, error) {
+	query := `
+		SELECT id, name, resource, action
+		FROM permissions
+		ORDER BY name ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPermissions(rows)
+}
+
+func (r *permissionPostgres) GetPermissionsByRoleID(ctx context.Context, roleID string) ([]model.Permission, error) {
+	query := `
+		SELECT p.id, p.name, p.resource, p.action
+		FROM permissions p
+		JOIN role_permissions rp ON rp.permission_id = p.id
+		WHERE rp.role_id = $1
+		ORDER BY p.name ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPermissions(rows)
+}
+
+func (r *permissionPostgres) Grant(ctx context.Context, roleID string, permissionID string) error {
+	query := `
+		INSERT INTO role_permissions (role_id, permission_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, roleID, permissionID)
+
+	// foreign key violation → role atau permission tidak ada
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return ErrRoleOrPermissionNotFound
+	}
+
+	return err
+}
+
+func (r *permissionPostgres) Revoke(ctx context.Context, roleID string, permissionID string) error {
+	query := `
+		DELETE FROM role_permissions
+		WHERE role_id = $1
+		  AND permission_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, roleID, permissionID)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrRoleOrPermissionNotFound
+	}
+
+	return nil
+}
+
+func scanPermissions(rows *sql.Rows) ([]model.Permission, error) {
+	permissions := []model.Permission{}
+
+	for rows.Next() {
+		var p model.Permission
+		if err := rows.Scan(
+			&p.ID,
+			&p.Name,
+			&p.Resource,
+			&p.Action,
+		); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"context"
+	"database/sql"
+)
+
+var (
+	ErrPortfolioNotFound    = NotFound("portfolio not found")
+	ErrVerificationNotFound = NotFound("achievement is not verified")
+)
+
+// PortfolioRepository membaca data portfolio publik dan bukti verifikasi prestasi.
+// Hanya prestasi dengan status verified yang pernah dikembalikan.
+type PortfolioRepository interface {
+	GetProfile(ctx context.Context, username string) (*model.PortfolioProfile, error)
+	ListPublicVerified(ctx context.Context, studentID string) ([]model.VerificationRecord, error)
+	FindVerification(ctx context.Context, mongoAchievementID string) (*model.VerificationRecord, error)
+}
+
+type portfolioPostgres struct {
+	db *sql.DB
+}
+
+func NewPortfolioRepository(db *sql.DB) PortfolioRepository {
+	return &portfolioPostgres{db: db}
+}
+
+// GetProfile: hanya mahasiswa dengan akun aktif yang punya portfolio
+func (r *portfolioPostgres) GetProfile(ctx context.Context, username string) (*model.PortfolioProfile, error) {
+	query := `
+		SELECT
+			s.id,
+			u.id,
+			u.username,
+			u.full_name,
+			COALESCE(s.program_study, ''),
+			COALESCE(s.academic_year, '')
+		FROM users u
+		JOIN students s ON s.user_id = u.id
+		WHERE u.username = $1
+		  AND u.is_active = TRUE
+	`
+
+	var p model.PortfolioProfile
+	err := r.db.QueryRowContext(ctx, query, username).Scan(
+		&p.StudentID,
+		&p.UserID,
+		&p.Username,
+		&p.FullName,
+		&p.ProgramStudy,
+		&p.AcademicYear,
+	)
+	if err != nil {
+		return nil, notFound(err, ErrPortfolioNotFound.Error())
+	}
+
+	return &p, nil
+}
+
+// ListPublicVerified: prestasi verified yang dipublikasikan mahasiswa, terbaru lebih dulu
+func (r *portfolioPostgres) ListPublicVerified(ctx context.Context, studentID string) ([]model.VerificationRecord, error) {
+	query := `
+		SELECT
+			ar.mongo_achievement_id,
+			ar.verified_at,
+			COALESCE(v.full_name, ''),
+			ar.points,
+			ar.is_public
+		FROM achievement_references ar
+		LEFT JOIN users v ON v.id = ar.verified_by
+		WHERE ar.student_id = $1
+		  AND ar.status = 'verified'
+		  AND ar.is_public
+		ORDER BY ar.verified_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []model.VerificationRecord{}
+	for rows.Next() {
+		var rec model.VerificationRecord
+		if err := rows.Scan(
+			&rec.MongoAchievementID,
+			&rec.VerifiedAt,
+			&rec.VerifierName,
+			&rec.Points,
+			&rec.IsPublic,
+		); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+
+	return records, rows.Err()
+}
+
+// FindVerification mengembalikan bukti verifikasi terbaru, ErrVerificationNotFound jika belum verified
+func (r *portfolioPostgres) FindVerification(ctx context.Context, mongoAchievementID string) (*model.VerificationRecord, error) {
+	query := `
+		SELECT
+			ar.mongo_achievement_id,
+			COALESCE(u.full_name, ''),
+			ar.verified_at,
+			COALESCE(v.full_name, ''),
+			ar.points,
+			ar.is_public
+		FROM achievement_references ar
+		JOIN students s ON s.id = ar.student_id
+		JOIN users u ON u.id = s.user_id
+		LEFT JOIN users v ON v.id = ar.verified_by
+		WHERE ar.mongo_achievement_id = $1
+		  AND ar.status = 'verified'
+		ORDER BY ar.created_at DESC
+		LIMIT 1
+	`
+
+	var rec model.VerificationRecord
+	err := r.db.QueryRowContext(ctx, query, mongoAchievementID).Scan(
+		&rec.MongoAchievementID,
+		&rec.StudentName,
+		&rec.VerifiedAt,
+		&rec.VerifierName,
+		&rec.Points,
+		&rec.IsPublic,
+	)
+	if err != nil {
+		return nil, notFound(err, ErrVerificationNotFound.Error())
+	}
+
+	return &rec, nil
+}
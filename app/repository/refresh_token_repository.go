@@ -0,0 +1,136 @@
+package repository
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenExpired  = errors.New("refresh token expired")
+	ErrRefreshTokenReused   = errors.New("refresh token reuse detected")
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *model.RefreshToken) error
+	Rotate(ctx context.Context, oldHash string, next *model.RefreshToken) (*model.RefreshToken, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+}
+
+type refreshTokenPostgres struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) RefreshTokenRepository {
+	return &refreshTokenPostgres{db: db}
+}
+
+func (r *refreshTokenPostgres) Create(ctx context.Context, token *model.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+	)
+
+	return err
+}
+
+// Rotate menandai token lama sebagai terpakai dan menyimpan token pengganti
+// dalam satu transaksi. Jika token lama sudah pernah dipakai atau di-revoke,
+// seluruh family di-revoke dan ErrRefreshTokenReused dikembalikan.
+func (r *refreshTokenPostgres) Rotate(ctx context.Context, oldHash string, next *model.RefreshToken) (*model.RefreshToken, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT id, user_id, family_id, expires_at, used_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+
+	old := model.RefreshToken{TokenHash: oldHash}
+	err = tx.QueryRowContext(ctx, query, oldHash).Scan(
+		&old.ID,
+		&old.UserID,
+		&old.FamilyID,
+		&old.ExpiresAt,
+		&old.UsedAt,
+		&old.RevokedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// token lama dipakai ulang → kemungkinan dicuri, matikan seluruh sesi
+	if old.UsedAt != nil || old.RevokedAt != nil {
+		if _, err := tx.ExecContext(ctx, revokeFamilyQuery, old.FamilyID); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	if time.Now().After(old.ExpiresAt) {
+		return nil, ErrRefreshTokenExpired
+	}
+
+	next.UserID = old.UserID
+	next.FamilyID = old.FamilyID
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE refresh_tokens
+		SET used_at = NOW(),
+		    replaced_by = $1
+		WHERE id = $2
+	`, next.ID, old.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, next.ID, next.UserID, next.FamilyID, next.TokenHash, next.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &old, nil
+}
+
+func (r *refreshTokenPostgres) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.ExecContext(ctx, revokeFamilyQuery, familyID)
+	return err
+}
+
+const revokeFamilyQuery = `
+	UPDATE refresh_tokens
+	SET revoked_at = NOW()
+	WHERE family_id = $1
+	  AND revoked_at IS NULL
+`
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// RevocationRepository menyimpan JTI token yang di-logout di PostgreSQL,
+// sehingga revocation tetap berlaku setelah restart dan di semua instance.
+// Memenuhi interface middleware.RevocationStore.
+type RevocationRepository interface {
+	Revoke(ctx context.Context, jti string, exp time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type revocationPostgres struct {
+	db *sql.DB
+}
+
+func NewRevocationRepository(db *sql.DB) RevocationRepository {
+	return &revocationPostgres{db: db}
+}
+
+func (r *revocationPostgres) Revoke(ctx context.Context, jti string, exp time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, jti, exp)
+	return err
+}
+
+func (r *revocationPostgres) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM revoked_tokens
+			WHERE jti = $1
+			  AND expires_at > NOW()
+		)
+	`
+
+	var revoked bool
+	err := r.db.QueryRowContext(ctx, query, jti).Scan(&revoked)
+	if err != nil {
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+func (r *revocationPostgres) PurgeExpired(ctx context.Context) (int64, error) {
+	query := `
+		DELETE FROM revoked_tokens
+		WHERE expires_at <= NOW()
+	`
+
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
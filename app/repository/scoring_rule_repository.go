@@ -0,0 +1,149 @@
+package repository
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrScoringRuleExists   = Conflict("scoring rule with the same criteria already exists")
+	ErrScoringRuleNotFound = NotFound("scoring rule not found")
+)
+
+type ScoringRuleRepository interface {
+	GetAll(ctx context.Context) ([]model.ScoringRule, error)
+	Create(ctx context.Context, req *model.ScoringRuleRequest) (*model.ScoringRule, error)
+	Update(ctx context.Context, id string, req *model.ScoringRuleRequest) (*model.ScoringRule, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type scoringRulePostgres struct {
+	db *sql.DB
+}
+
+func NewScoringRuleRepository(db *sql.DB) ScoringRuleRepository {
+	return &scoringRulePostgres{db: db}
+}
+
+const scoringRuleColumns = `id, achievement_type, competition_level, rank, medal_type, points, created_at, updated_at`
+
+func (r *scoringRulePostgres) GetAll(ctx context.Context) ([]model.ScoringRule, error) {
+	query := `
+		SELECT ` + scoringRuleColumns + `
+		FROM scoring_rules
+		ORDER BY achievement_type, competition_level NULLS FIRST, rank NULLS FIRST, medal_type NULLS FIRST
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []model.ScoringRule{}
+	for rows.Next() {
+		rule, err := scanScoringRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (r *scoringRulePostgres) Create(ctx context.Context, req *model.ScoringRuleRequest) (*model.ScoringRule, error) {
+	query := `
+		INSERT INTO scoring_rules (achievement_type, competition_level, rank, medal_type, points)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + scoringRuleColumns
+
+	rule, err := scanScoringRule(r.db.QueryRowContext(
+		ctx,
+		query,
+		req.AchievementType,
+		req.CompetitionLevel,
+		req.Rank,
+		req.MedalType,
+		req.Points,
+	))
+
+	return rule, translateScoringRuleError(err)
+}
+
+func (r *scoringRulePostgres) Update(ctx context.Context, id string, req *model.ScoringRuleRequest) (*model.ScoringRule, error) {
+	query := `
+		UPDATE scoring_rules
+		SET achievement_type = $1,
+		    competition_level = $2,
+		    rank = $3,
+		    medal_type = $4,
+		    points = $5,
+		    updated_at = NOW()
+		WHERE id = $6
+		RETURNING ` + scoringRuleColumns
+
+	rule, err := scanScoringRule(r.db.QueryRowContext(
+		ctx,
+		query,
+		req.AchievementType,
+		req.CompetitionLevel,
+		req.Rank,
+		req.MedalType,
+		req.Points,
+		id,
+	))
+
+	return rule, translateScoringRuleError(err)
+}
+
+func (r *scoringRulePostgres) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM scoring_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrScoringRuleNotFound
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanScoringRule(row rowScanner) (*model.ScoringRule, error) {
+	var rule model.ScoringRule
+	err := row.Scan(
+		&rule.ID,
+		&rule.AchievementType,
+		&rule.CompetitionLevel,
+		&rule.Rank,
+		&rule.MedalType,
+		&rule.Points,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// unique violation → kriteria yang sama sudah ada
+func translateScoringRuleError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrScoringRuleExists
+	}
+	return notFound(err, ErrScoringRuleNotFound.Error())
+}
@@ -4,16 +4,19 @@ import (
 	model "PROJECTUAS_BE/app/Model"
 	"context"
 	"database/sql"
-	"errors"
+
+	"github.com/lib/pq"
 )
 
+var ErrStudentNotFound = NotFound("student not found")
+
 type StudentRepository interface {
 	CreateStudent(userID string) error
 	GetStudentByUserID(userID string) (*model.Student, error)
-	GetAllStudents() ([]model.Student, error)
-	Submit(ctx context.Context, ref *model.AchievementReference) error
+	GetAllStudents(ctx context.Context, filter model.StudentFilter, q model.ListQuery) ([]model.Student, model.PageInfo, error)
 	GetStudentIDByUserID(ctx context.Context, userID string) (string, error)
 	UpdateAdvisor(ctx context.Context, studentID string, advisorID string) error
+	GetAdviseeUserIDs(ctx context.Context, lecturerUserID string) ([]string, error)
 }
 
 type StudentPostgres struct {
@@ -26,59 +29,58 @@ type StudentPostgres struct {
 
 func (r *StudentPostgres) CreateStudent(userID string) error {
 	query := `
-		INSERT INTO student (user_id)
+		INSERT INTO students (user_id)
 		VALUES ($1)
 	`
 	_, err := r.DB.Exec(query, userID)
 	return err
 }
 
-func (r *StudentPostgres) GetAllStudents() ([]model.Student, error) {
-	query := `
-		SELECT
+// GetAllStudents: sort full_name | username | program_study | academic_year | created_at
+func (r *StudentPostgres) GetAllStudents(ctx context.Context, filter model.StudentFilter, q model.ListQuery) ([]model.Student, model.PageInfo, error) {
+	list := pgList{
+		Columns: `
 			s.id AS student_id,
 			s.user_id,
 			s.academic_year,
 			s.program_study,
 			u.username,
 			u.full_name,
-			u.email
-		FROM students s	
-		JOIN users u ON u.id = s.user_id
-	`
-
-	rows, err := r.DB.Query(query)
-	if err != nil {
-		return nil, err
+			u.email`,
+		From:     "FROM students s JOIN users u ON u.id = s.user_id",
+		IDColumn: "s.id",
+		Sorts: map[string]string{
+			"full_name":     "u.full_name",
+			"username":      "u.username",
+			"program_study": "s.program_study",
+			"academic_year": "s.academic_year",
+			"created_at":    "s.created_at",
+		},
+		DefaultSort: "full_name",
 	}
-	defer rows.Close()
-
-	var students []model.Student
 
-	for rows.Next() {
-		student := model.Student{}
-		err := rows.Scan(
-			&student.StudentID,
-			&student.UserID,
-			&student.AcademicYear,
-			&student.ProgramStudy,
-			&student.Username,
-			&student.Fullname,
-			&student.Email,
-		)
-
-		if err != nil {
-			return nil, err
-		}
-
-		students = append(students, student)
+	if filter.ProgramStudy != "" {
+		list.filter("s.program_study = ?", filter.ProgramStudy)
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
+	if filter.AcademicYear != "" {
+		list.filter("s.academic_year = ?", filter.AcademicYear)
+	}
+	if filter.Owners != nil {
+		// slice kosong = tidak ada mahasiswa yang terlihat
+		list.filter("s.user_id = ANY(?)", pq.Array(filter.Owners))
 	}
 
-	return students, nil
+	return listPage(ctx, r.DB, list, q, func(s *model.Student) []interface{} {
+		return []interface{}{
+			&s.StudentID,
+			&s.UserID,
+			&s.AcademicYear,
+			&s.ProgramStudy,
+			&s.Username,
+			&s.Fullname,
+			&s.Email,
+		}
+	})
 }
 
 func (r *StudentPostgres) GetStudentByUserID(userID string) (*model.Student, error) {
@@ -110,7 +112,7 @@ func (r *StudentPostgres) GetStudentByUserID(userID string) (*model.Student, err
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("student not found")
+		return nil, ErrStudentNotFound
 	}
 
 	if err != nil {
@@ -120,25 +122,6 @@ func (r *StudentPostgres) GetStudentByUserID(userID string) (*model.Student, err
 	return &student, nil
 }
 
-func (r *StudentPostgres) Submit(ctx context.Context, ref *model.AchievementReference) error {
-	query := `
-        INSERT INTO achievement_references 
-        (id, student_id, mongo_achievement_id, status, submitted_at)
-        VALUES  ($1, $2, $3, $4, $5)`
-
-	_, err := r.DB.ExecContext(
-		ctx,
-		query,
-		ref.ID,
-		ref.StudentID,
-		ref.MongoAchievementID,
-		ref.Status,
-		ref.SubmittedAt,
-	)
-
-	return err
-}
-
 func (r *StudentPostgres) GetStudentIDByUserID(ctx context.Context, userID string) (string, error) {
 	var studentID string
 
@@ -150,7 +133,7 @@ func (r *StudentPostgres) GetStudentIDByUserID(ctx context.Context, userID strin
 
 	err := r.DB.QueryRowContext(ctx, query, userID).Scan(&studentID)
 	if err != nil {
-		return "", err
+		return "", notFound(err, ErrStudentNotFound.Error())
 	}
 
 	return studentID, nil
@@ -175,10 +158,37 @@ func (r *StudentPostgres) UpdateAdvisor(ctx context.Context, studentID string, a
 
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return sql.ErrNoRows
+		return ErrStudentNotFound
 	}
 
 	return nil
 }
 
 
+
+// GetAdviseeUserIDs mengembalikan users.id mahasiswa bimbingan dosen (lecturerUserID = users.id dosen)
+func (r *StudentPostgres) GetAdviseeUserIDs(ctx context.Context, lecturerUserID string) ([]string, error) {
+	query := `
+		SELECT s.user_id
+		FROM students s
+		JOIN lecturers l ON l.id = s.advisor_id
+		WHERE l.user_id = $1
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, lecturerUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
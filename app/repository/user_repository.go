@@ -2,13 +2,27 @@ package repository
 
 import (
 	model "PROJECTUAS_BE/app/Model"
+	"context"
 	"database/sql"
 	"fmt"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrRoleNotFound         = Invalid("role not found")
+	ErrUserAlreadyExists    = Conflict("username or email already exists")
+	ErrAdvisorNotFound      = Invalid("advisor not found")
+	ErrStudentProfileEmpty  = Invalid("student profile is required for role mahasiswa")
+	ErrLecturerProfileEmpty = Invalid("lecturer profile is required for role dosen")
+	ErrUnsupportedRole      = Invalid("role is not supported for user creation")
+	ErrUserNotFound         = NotFound("user not found")
 )
 
 type UserRepository interface {
 	CreateUser(username, email, password, roleID, fullname string) (string, error)
-	GetAllUsers() ([]model.User, error)
+	CreateUserWithProfile(ctx context.Context, req *model.CreateUserRequest, passwordHash string) (*model.UserProfileResponse, error)
+	GetAllUsers(ctx context.Context, filter model.UserFilter, q model.ListQuery) ([]model.User, model.PageInfo, error)
 	GetRoleByUserID(userID string) (string, error)
 	GetRoleNameByRoleID(roleID string) (string, error)
 	GetUserByID(id string) (*model.User, error)
@@ -36,45 +50,133 @@ func (r *userPostgres) GetUserRole(userID int) (string, error) {
 	return role, err
 }
 
-func (r *userPostgres) GetPermissionsByRole(roleName string) ([]string, error) {
+func (r *userPostgres) CreateUser(username, email, password, roleID, fullname string) (string, error) {
 	query := `
-        SELECT p.name, p.resource, p.action
-        FROM permissions p
-        JOIN role_permissions rp ON rp.permission_id = p.id
-        JOIN roles r ON r.id = rp.role_id
-        WHERE r.name=$1
-    `
-	rows, err := r.db.Query(query, roleName)
+		INSERT INTO users (username, email, password_hash, role_id, full_name)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id;
+	`
+
+	var userID string
+	err := r.db.QueryRow(query, username, email, password, roleID, fullname).Scan(&userID)
+	if err != nil {
+		fmt.Println("DB ERROR:", err)
+		return "", err
+	}
+
+	return userID, nil
+}
+
+// CreateUserWithProfile membuat user beserta baris students / lecturers dalam satu transaksi.
+// Jika salah satu insert gagal, seluruh perubahan di-rollback.
+func (r *userPostgres) CreateUserWithProfile(ctx context.Context, req *model.CreateUserRequest, passwordHash string) (*model.UserProfileResponse, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	defer tx.Rollback()
 
-	var perms []string
-	for rows.Next() {
-		var p string
-		rows.Scan(&p)
-		perms = append(perms, p)
+	profile := &model.UserProfileResponse{
+		Username: req.Username,
+		Email:    req.Email,
+		Fullname: req.Fullname,
+		RoleID:   req.RoleID,
 	}
 
-	return perms, nil
-}
+	err = tx.QueryRowContext(ctx, "SELECT name FROM roles WHERE id = $1", req.RoleID).Scan(&profile.Role)
+	if err == sql.ErrNoRows {
+		return nil, ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// role yang tidak dikenal ditolak sebelum insert, agar tidak ada user tanpa profil
+	switch profile.Role {
+	case model.RoleStudent:
+		if req.Student == nil {
+			return nil, ErrStudentProfileEmpty
+		}
+	case model.RoleLecturer:
+		if req.Lecturer == nil {
+			return nil, ErrLecturerProfileEmpty
+		}
+	case model.RoleAdmin:
+		// admin tidak punya tabel profil
+	default:
+		return nil, ErrUnsupportedRole
+	}
 
-func (r *userPostgres) CreateUser(username, email, password, roleID, fullname string) (string, error) {
 	query := `
 		INSERT INTO users (username, email, password_hash, role_id, full_name)
 		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id;
+		RETURNING id, is_active;
 	`
 
-	var userID string
-	err := r.db.QueryRow(query, username, email, password, roleID, fullname).Scan(&userID)
+	err = tx.QueryRowContext(ctx, query, req.Username, req.Email, passwordHash, req.RoleID, req.Fullname).
+		Scan(&profile.ID, &profile.IsActive)
 	if err != nil {
-		fmt.Println("DB ERROR:", err)
-		return "", err
+		return nil, translateUserError(err)
 	}
 
-	return userID, nil
+	switch profile.Role {
+	case model.RoleStudent:
+		student := &model.StudentProfile{
+			AcademicYear: req.Student.AcademicYear,
+			ProgramStudy: req.Student.ProgramStudy,
+			AdvisorID:    req.Student.AdvisorID,
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO students (user_id, academic_year, program_study, advisor_id)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, profile.ID, student.AcademicYear, student.ProgramStudy, student.AdvisorID).Scan(&student.ID)
+		if err != nil {
+			return nil, translateUserError(err)
+		}
+
+		profile.Student = student
+
+	case model.RoleLecturer:
+		lecturer := &model.LecturerProfile{
+			Department: req.Lecturer.Department,
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO lecturers (user_id, department)
+			VALUES ($1, $2)
+			RETURNING id
+		`, profile.ID, lecturer.Department).Scan(&lecturer.ID)
+		if err != nil {
+			return nil, translateUserError(err)
+		}
+
+		profile.Lecturer = lecturer
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// translateUserError mengubah error constraint PostgreSQL menjadi error yang dikenali service
+func translateUserError(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if !ok {
+		return err
+	}
+
+	switch pqErr.Code {
+	case "23505": // unique_violation
+		return ErrUserAlreadyExists
+	case "23503": // foreign_key_violation (advisor_id)
+		return ErrAdvisorNotFound
+	}
+
+	return err
 }
 
 func (r *userPostgres) GetRoleNameByRoleID(roleID string) (string, error) {
@@ -84,7 +186,7 @@ func (r *userPostgres) GetRoleNameByRoleID(roleID string) (string, error) {
 	err := r.db.QueryRow(query, roleID).Scan(&name)
 
 	if err == sql.ErrNoRows {
-		return "", fmt.Errorf("role not found")
+		return "", ErrRoleNotFound
 	}
 
 	if err != nil {
@@ -112,38 +214,38 @@ func (r *userPostgres) GetRoleByUserID(userID string) (string, error) {
 	return role, nil
 }
 
-func (r *userPostgres) GetAllUsers() ([]model.User, error) {
-	query := `
-		SELECT id, username, email, password_hash, role_id, full_name, is_active
-		FROM users;
-	`
-
-	rows, err := r.db.Query(query)
-	if err != nil {
-		return nil, err
+// GetAllUsers: sort username | email | full_name | created_at. password_hash tidak ikut dibaca.
+func (r *userPostgres) GetAllUsers(ctx context.Context, filter model.UserFilter, q model.ListQuery) ([]model.User, model.PageInfo, error) {
+	list := pgList{
+		Columns:  "u.id, u.username, u.email, u.role_id, u.full_name, u.is_active",
+		From:     "FROM users u JOIN roles ro ON ro.id = u.role_id",
+		IDColumn: "u.id",
+		Sorts: map[string]string{
+			"username":   "u.username",
+			"email":      "u.email",
+			"full_name":  "u.full_name",
+			"created_at": "u.created_at",
+		},
+		DefaultSort: "username",
 	}
-	defer rows.Close()
 
-	var users []model.User
+	if filter.Role != "" {
+		list.filter("ro.name = ?", filter.Role)
+	}
+	if filter.IsActive != nil {
+		list.filter("u.is_active = ?", *filter.IsActive)
+	}
 
-	for rows.Next() {
-		u := model.User{}
-		err := rows.Scan(
+	return listPage(ctx, r.db, list, q, func(u *model.User) []interface{} {
+		return []interface{}{
 			&u.ID,
 			&u.Username,
 			&u.Email,
-			&u.Password,
 			&u.RoleID,
 			&u.Fullname,
 			&u.Is_active,
-		)
-		if err != nil {
-			return nil, err
 		}
-		users = append(users, u)
-	}
-
-	return users, nil
+	})
 }
 
 func (r *userPostgres) GetUserByID(id string) (*model.User, error) {
@@ -164,7 +266,7 @@ func (r *userPostgres) GetUserByID(id string) (*model.User, error) {
 		&user.Is_active)
 
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, ErrUserNotFound.Error())
 	}
 
 	return &user, nil
@@ -181,7 +283,7 @@ func (r *userPostgres) UpdateUserByID(id string, name string, email string) erro
 
 	res, err := r.db.Exec(query, name, email, id)
 	if err != nil {
-		return err
+		return translateUserError(err)
 	}
 
 	rows, err := res.RowsAffected()
@@ -190,7 +292,7 @@ func (r *userPostgres) UpdateUserByID(id string, name string, email string) erro
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
 	}
 
 	return nil
@@ -213,7 +315,7 @@ func (r *userPostgres) DeleteUserByID(id string) error {
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
 	}
 
 	return nil
@@ -0,0 +1,145 @@
+package response
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/validation"
+	"net/url"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Envelope adalah bentuk semua response API.
+//
+//	sukses: {"success": true, "message": "...", "data": {...}, "request_id": "..."}
+//	list:   {"success": true, "data": [...], "meta": {"total": 42, "next_cursor": "...", "next": "..."}, ...}
+//	gagal:  {"success": false, "error": {"code": "NOT_FOUND", "message": "...", "details": [...]}, "request_id": "..."}
+type Envelope struct {
+	Success   bool       `json:"success"`
+	Message   string     `json:"message,omitempty"`
+	Data      any        `json:"data,omitempty"`
+	Meta      any        `json:"meta,omitempty"` // PageInfo untuk endpoint list
+	Error     *ErrorBody `json:"error,omitempty"`
+	RequestID string     `json:"request_id,omitempty"`
+}
+
+type ErrorBody struct {
+	Code    string                  `json:"code"`
+	Message string                  `json:"message"`
+	Details []validation.FieldError `json:"details,omitempty"`
+}
+
+// Kode error yang stabil untuk client, tidak tergantung teks pesan
+const (
+	CodeBadRequest         = "BAD_REQUEST"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeNotFound           = "NOT_FOUND"
+	CodeConflict           = "CONFLICT"
+	CodeValidationFailed   = "VALIDATION_FAILED"
+	CodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMedia   = "UNSUPPORTED_MEDIA_TYPE"
+	CodeRangeNotSatisfied  = "RANGE_NOT_SATISFIABLE"
+	CodeTooManyRequests    = "TOO_MANY_REQUESTS"
+	CodeInternal           = "INTERNAL_ERROR"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+)
+
+// CodeForStatus memetakan status HTTP ke kode error
+func CodeForStatus(status int) string {
+	switch status {
+	case fiber.StatusBadRequest:
+		return CodeBadRequest
+	case fiber.StatusUnauthorized:
+		return CodeUnauthorized
+	case fiber.StatusForbidden:
+		return CodeForbidden
+	case fiber.StatusNotFound:
+		return CodeNotFound
+	case fiber.StatusConflict:
+		return CodeConflict
+	case fiber.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case fiber.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case fiber.StatusUnsupportedMediaType:
+		return CodeUnsupportedMedia
+	case fiber.StatusRequestedRangeNotSatisfiable:
+		return CodeRangeNotSatisfied
+	case fiber.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case fiber.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	}
+
+	if status >= 500 {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// RequestID diisi oleh middleware requestid Fiber
+func RequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals("requestid").(string)
+	return id
+}
+
+func OK(c *fiber.Ctx, message string, data any) error {
+	return JSON(c, fiber.StatusOK, message, data)
+}
+
+func Created(c *fiber.Ctx, message string, data any) error {
+	return JSON(c, fiber.StatusCreated, message, data)
+}
+
+func JSON(c *fiber.Ctx, status int, message string, data any) error {
+	return c.Status(status).JSON(Envelope{
+		Success:   true,
+		Message:   message,
+		Data:      data,
+		RequestID: RequestID(c),
+	})
+}
+
+// Page mengirim satu halaman list beserta link ke halaman berikutnya
+func Page(c *fiber.Ctx, data any, info model.PageInfo) error {
+	info.Next = nextLink(c, info)
+
+	return c.Status(fiber.StatusOK).JSON(Envelope{
+		Success:   true,
+		Data:      data,
+		Meta:      info,
+		RequestID: RequestID(c),
+	})
+}
+
+// nextLink memakai query string request saat ini: mode cursor → ?cursor=, mode offset → ?page=
+func nextLink(c *fiber.Ctx, info model.PageInfo) string {
+	if !info.HasMore {
+		return ""
+	}
+
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return ""
+	}
+
+	if info.Page > 0 {
+		query.Set("page", strconv.Itoa(info.Page+1))
+	} else {
+		query.Set("cursor", info.NextCursor)
+	}
+
+	return c.Path() + "?" + query.Encode()
+}
+
+func Error(c *fiber.Ctx, status int, message string, details []validation.FieldError) error {
+	return c.Status(status).JSON(Envelope{
+		Error: &ErrorBody{
+			Code:    CodeForStatus(status),
+			Message: message,
+			Details: details,
+		},
+		RequestID: RequestID(c),
+	})
+}
@@ -3,11 +3,15 @@ package service
 import (
 	model "PROJECTUAS_BE/app/Model"
 	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
+	"PROJECTUAS_BE/app/signing"
+	"PROJECTUAS_BE/app/storage"
+	"PROJECTUAS_BE/app/validation"
 	"PROJECTUAS_BE/middleware"
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -16,30 +20,50 @@ import (
 )
 
 type AchievementService struct {
-	Repo repository.AchievementRepository
+	Repo      repository.AchievementRepository
+	Lifecycle *AchievementLifecycle
+	Blobs     storage.BlobStore // file lampiran
+	Signer    *signing.Signer   // signed URL download lampiran
+	Policy    AttachmentPolicy
 }
 
-func NewAchievementService(repo repository.AchievementRepository) *AchievementService {
+func NewAchievementService(repo repository.AchievementRepository, lifecycle *AchievementLifecycle, blobs storage.BlobStore, signer *signing.Signer, policy AttachmentPolicy) *AchievementService {
 	return &AchievementService{
-		Repo: repo,
+		Repo:      repo,
+		Lifecycle: lifecycle,
+		Blobs:     blobs,
+		Signer:    signer,
+		Policy:    policy,
 	}
 }
 
+// GetAllAchievements: GET /api/achievements?status=&type=&tags=a,b&student=&from=&to=&sort=-created_at&limit=&page=|cursor=
+// Hasil dibatasi visibilitas role pemanggil, lihat visibleOwners.
 func (s *AchievementService) GetAllAchievements(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
 
-	// Panggil repository langsung (service disatukan disini)
-	achievements, err := s.Repo.GetAll()
+	filter, err := parseAchievementFilter(c)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get achievements")
+		return err
 	}
+	filter.StudentID = c.Query("student")
 
-	return c.JSON(fiber.Map{
-		"status": "success",
-		"data":   achievements,
-	})
+	if filter.Owners, err = s.visibleOwners(context.Background(), claims); err != nil {
+		return err
+	}
+
+	return s.listAchievements(c, filter)
 }
 
 func (s *AchievementService) GetAchievementsByID(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
 	id := c.Params("id")
 	if id == "" {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid achievement id")
@@ -55,10 +79,11 @@ func (s *AchievementService) GetAchievementsByID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "achievement not found")
 	}
 
-	return c.JSON(fiber.Map{
-		"status": "success",
-		"data":   achievement,
-	})
+	if err := s.canView(context.Background(), claims, achievement.StudentID); err != nil {
+		return err
+	}
+
+	return response.OK(c, "", achievement)
 }
 
 func (s *AchievementService) CreateAchievements(c *fiber.Ctx) error {
@@ -70,17 +95,15 @@ func (s *AchievementService) CreateAchievements(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	userClaims := claims.(*middleware.Claims)
-
-	// Hanya admin yang bisa
-	if userClaims.Role != "mahasiswa" {
-		return fiber.NewError(fiber.StatusForbidden, "Access ditolak, hanya mahasiswa yang boleh mengakses")
-	}
-
 	// Bind input
 	input := new(model.Achievement)
-	if err := c.BodyParser(input); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	if err := parseBody(c, input); err != nil {
+		return err
+	}
+
+	// details harus sesuai achievementType
+	if err := validateAchievementDetails(input); err != nil {
+		return err
 	}
 
 	// Set required fields
@@ -88,19 +111,31 @@ func (s *AchievementService) CreateAchievements(c *fiber.Ctx) error {
 	input.StudentID = studentId.(string)
 	input.CreatedAt = time.Now()
 
+	// poin dihitung dari rubrik saat verifikasi, status hanya ditulis oleh outbox worker
+	input.Points = 0
+	input.Status = ""
+	input.VerifiedAt = nil
+	input.StatusSeq = 0
+
 	err := s.Repo.Create(context.Background(), input)
 
 	if err != nil {
 		fmt.Println("ERROR SAVE ACHIEVEMENT:", err) // debug log
 
-		return fiber.NewError(fiber.StatusInternalServerError,
-			fmt.Sprintf("Failed to save achievement: %v", err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to save achievement")
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "Achievement added successfully",
-		"data":    input,
-	})
+	// Catat sebagai draft di PostgreSQL, batalkan dokumen Mongo jika gagal
+	if err := s.Lifecycle.CreateDraft(context.Background(), input.ID, input.StudentID); err != nil {
+		s.Repo.Delete(context.Background(), input.ID)
+
+		if errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to register achievement")
+	}
+
+	return response.Created(c, "Achievement added successfully", input)
 }
 
 func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
@@ -115,13 +150,6 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	userClaims := claims.(*middleware.Claims)
-
-	// Hanya admin yang bisa
-	if userClaims.Role != "mahasiswa" {
-		return fiber.NewError(fiber.StatusForbidden, "Access ditolak, hanya mahasiswa yang boleh mengakses")
-	}
-
 	// GET ID FROM PARAM
 	id := c.Params("id")
 
@@ -136,20 +164,29 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusForbidden, "You cannot edit someone else's achievement")
 	}
 
+	// Hanya draft / rejected yang boleh diedit
+	if _, _, err := s.Lifecycle.Guard(context.Background(), id, ActionEdit); err != nil {
+		return err
+	}
+
 	// Bind request body
 	req := new(model.Achievement)
-	if err := c.BodyParser(req); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	if err := parseBody(c, req); err != nil {
+		return err
+	}
+
+	if err := validateAchievementDetails(req); err != nil {
+		return err
 	}
 
-	// Data yang boleh diupdate
+	// Data yang boleh diupdate (points dihitung server saat verifikasi)
 	update := bson.M{
 		"title":           req.Title,
-		"Tags":            req.Tags,
-		"AchievementType": req.AchievementType,
-		"point":           req.Points,
+		"tags":            req.Tags,
+		"achievementType": req.AchievementType,
+		"details":         req.Details,
 		"description":     req.Description,
-		"updated_at":      time.Now(),
+		"updatedAt":       time.Now(),
 	}
 
 	// Lakukan update
@@ -158,10 +195,7 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update achievement")
 	}
 
-	return c.JSON(fiber.Map{
-		"message": "Achievement updated successfully",
-		"id":      id,
-	})
+	return response.OK(c, "Achievement updated successfully", fiber.Map{"id": id})
 }
 
 func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
@@ -175,11 +209,6 @@ func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
 
 	userClaims := claims.(*middleware.Claims)
 
-	// Hanya admin yang bisa
-	if userClaims.Role != "mahasiswa" {
-		return fiber.NewError(fiber.StatusForbidden, "Access ditolak, hanya mahasiswa yang boleh mengakses")
-	}
-
 	id := c.Params("id")
 
 	// Cek apakah data exist
@@ -192,15 +221,29 @@ func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "achievement not found")
 	}
 
+	if achievement.StudentID != userClaims.UserID {
+		return fiber.NewError(fiber.StatusForbidden, "You cannot delete someone else's achievement")
+	}
+
+	// Prestasi yang sudah submitted / verified tidak boleh dihapus.
+	// Reference di PostgreSQL di-soft-delete (status deleted) sebelum dokumen Mongo dihapus;
+	// jika penghapusan Mongo gagal, consistency check akan menyelesaikannya.
+	if _, err := s.Lifecycle.Apply(context.Background(), id, ActionDelete, userClaims.UserID, nil); err != nil {
+		return err
+	}
+
 	// Hapus
 	err = s.Repo.Delete(context.Background(), id)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "failed to delete achievement")
 	}
 
-	return c.JSON(fiber.Map{
-		"message": "achievement deleted successfully",
-	})
+	// file yang gagal dihapus di sini akan dibersihkan AttachmentGC
+	for _, a := range achievement.Attachments {
+		s.removeAttachmentFiles(a)
+	}
+
+	return response.OK(c, "Achievement deleted successfully", nil)
 }
 
 func (s *AchievementService) GetStudentAchievements(c *fiber.Ctx) error {
@@ -214,32 +257,54 @@ func (s *AchievementService) GetStudentAchievements(c *fiber.Ctx) error {
 
 	userClaims := claims.(*middleware.Claims)
 
-	// Hanya admin yang bisa
-	if userClaims.Role != "mahasiswa" {
-		return fiber.NewError(fiber.StatusForbidden, "Access ditolak, hanya mahasiswa yang boleh mengakses")
-	}
-
 	studentID := userClaims.UserID
 	if studentID == "" {
 		return fiber.NewError(fiber.StatusBadRequest, "Student ID not found")
 	}
 
-	// ===== 3. Fetch Data =====
-	achievements, err := s.Repo.GetStudentByAchievement(
-		context.Background(),
-		studentID,
-	)
+	// filter dan paginasi sama dengan GET /achievements, pemilik selalu mahasiswa yang login
+	filter, err := parseAchievementFilter(c)
+	if err != nil {
+		return err
+	}
+	filter.StudentID = studentID
+
+	return s.listAchievements(c, filter)
+}
 
+func (s *AchievementService) listAchievements(c *fiber.Ctx, filter model.AchievementFilter) error {
+	query, err := parseListQuery(c, "-created_at")
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch achievements")
+		return err
 	}
 
-	// ===== 4. Response =====
-	return c.JSON(fiber.Map{
-		"message": "Student achievements fetched successfully",
-		"total":   len(achievements),
-		"data":    achievements,
-	})
+	achievements, page, err := s.Repo.List(context.Background(), filter, query)
+	if err != nil {
+		return err
+	}
+
+	return response.Page(c, achievements, page)
+}
+
+func parseAchievementFilter(c *fiber.Ctx) (model.AchievementFilter, error) {
+	filter := model.AchievementFilter{
+		Status:          c.Query("status"),
+		AchievementType: c.Query("type"),
+	}
+
+	if tags := c.Query("tags"); tags != "" {
+		filter.Tags = strings.Split(tags, ",")
+	}
+
+	var err error
+	if filter.From, err = parseReportDate(c.Query("from")); err != nil {
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid 'from' date, use YYYY-MM-DD")
+	}
+	if filter.To, err = parseReportDate(c.Query("to")); err != nil {
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid 'to' date, use YYYY-MM-DD")
+	}
+
+	return filter, nil
 }
 
 func (s *AchievementService) UploadAttachments(c *fiber.Ctx) error {
@@ -252,24 +317,20 @@ func (s *AchievementService) UploadAttachments(c *fiber.Ctx) error {
 
 	userClaims := claims.(*middleware.Claims)
 
-	// Hanya admin yang bisa
-	if userClaims.Role != "mahasiswa" {
-		return fiber.NewError(fiber.StatusForbidden, "Access ditolak, hanya mahasiswa yang boleh mengakses")
-	}
-
 	achievementID := c.Params("id")
 	if achievementID == "" {
 		return fiber.NewError(fiber.StatusBadRequest, "Achievement ID is required")
 	}
 
 	// ===== 3. Check ownership =====
-	achievement, err := s.Repo.FindById(context.Background(), achievementID)
+	// Attachment hanya bisa ditambah selama prestasi masih bisa diedit
+	achievement, err := s.editableAchievement(userClaims, achievementID)
 	if err != nil {
-		return fiber.NewError(fiber.StatusNotFound, "Achievment not found")
+		return err
 	}
 
-	if achievement.StudentID != userClaims.UserID {
-		return fiber.NewError(fiber.StatusForbidden, "You can only upload to your own achievement")
+	if len(achievement.Attachments) >= s.Policy.MaxCount {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("An achievement can have at most %d attachments", s.Policy.MaxCount))
 	}
 
 	// ===== 4. Get file =====
@@ -278,39 +339,60 @@ func (s *AchievementService) UploadAttachments(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "File is required")
 	}
 
-	// ===== 5. Save file =====
-	uploadDir := "./uploads/achievements"
-	os.MkdirAll(uploadDir, os.ModePerm)
+	// tipe file ditentukan dari isinya, bukan dari nama file atau header Content-Type
+	data, inspected, err := s.readAttachment(file)
+	if err != nil {
+		return err
+	}
 
-	ext := filepath.Ext(file.Filename)
-	fileName := uuid.New().String() + ext
-	filePath := filepath.Join(uploadDir, fileName)
+	for _, existing := range achievement.Attachments {
+		if existing.SHA256 == inspected.SHA256 {
+			return fiber.NewError(fiber.StatusConflict, "This file is already attached to the achievement")
+		}
+	}
 
-	if err := c.SaveFile(file, filePath); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to save file")
+	// ===== 5. Save file + preview =====
+	attachment, err := s.storeAttachment(data, inspected, file.Filename)
+	if err != nil {
+		return err
 	}
 
 	// ===== 6. Save metadata =====
-	attachment := model.Attachment{
-		FileName:   file.Filename,
-		FileUrl:    "/uploads/achievements/" + fileName,
-		FileType:   file.Header.Get("Content-Type"),
-		UploadedAt: time.Now(),
-	}
+	attachment.ID = uuid.New().String()
+	attachment.FileUrl = attachmentPath(achievementID, attachment.ID)
 
 	err = s.Repo.AddAttachment(
 		context.Background(),
 		achievementID,
 		attachment,
+		s.Policy.MaxCount,
 	)
 
 	if err != nil {
+		// file tanpa metadata tidak akan pernah direferensikan
+		s.removeAttachmentFiles(attachment)
+		if errors.Is(err, repository.ErrConflict) {
+			return err
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to save attachment")
 	}
 
 	// ===== 7. Response =====
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "Attachment uploaded successfully",
-		"data":    attachment,
-	})
+	return response.Created(c, "Attachment uploaded successfully", attachment)
+}
+
+// validateAchievementDetails: achievementType sudah divalidasi parseBody,
+// error details dikembalikan dengan path "details.<field>"
+func validateAchievementDetails(a *model.Achievement) error {
+	err := a.Details.Resolve(a.AchievementType)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		return fieldErrs.Prefix("details")
+	}
+
+	return validation.Errors{{Field: "details", Rule: "details", Message: err.Error()}}
 }
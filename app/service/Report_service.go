@@ -1,20 +1,26 @@
 package service
 
 import (
+	model "PROJECTUAS_BE/app/Model"
 	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
 	"PROJECTUAS_BE/middleware"
 	"context"
+	"sort"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type ReportService struct {
-	Repo repository.ReportRepository
+	Repo         repository.ReportRepository
+	Achievements repository.AchievementRepository
 }
 
-func NewReportService(repo repository.ReportRepository) *ReportService {
+func NewReportService(repo repository.ReportRepository, achievements repository.AchievementRepository) *ReportService {
 	return &ReportService{
-		Repo: repo,
+		Repo:         repo,
+		Achievements: achievements,
 	}
 }
 
@@ -29,23 +35,37 @@ func (s *ReportService) GetStatics(c *fiber.Ctx) error {
 	filter := repository.StatisticsFilter{}
 
 	switch userClaims.Role {
-	case "mahasiswa":
+	case middleware.RoleStudent:
 		filter.StudentID = &userClaims.UserID
 
-	case "lecturer":
+	case middleware.RoleLecturer:
 		filter.LecturerID = &userClaims.UserID
 
-	case "admin":
+	case middleware.RoleAdmin:
 		// no filter
 
 	default:
 		return fiber.NewError(fiber.StatusForbidden, "Access denied")
 	}
 
-	stats, err := s.Repo.GetStatics(
-		context.Background(),
-		filter,
-	)
+	// ?from=2025-01-01&to=2025-07-01&period=month|semester
+	query := model.StatisticsQuery{Period: c.Query("period", model.PeriodMonth)}
+	if query.Period != model.PeriodMonth && query.Period != model.PeriodSemester {
+		return fiber.NewError(fiber.StatusBadRequest, "period must be 'month' or 'semester'")
+	}
+
+	var err error
+	if query.From, err = parseReportDate(c.Query("from")); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid 'from' date, use YYYY-MM-DD")
+	}
+	if query.To, err = parseReportDate(c.Query("to")); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid 'to' date, use YYYY-MM-DD")
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return fiber.NewError(fiber.StatusBadRequest, "'from' must be before 'to'")
+	}
+
+	stats, err := s.BuildStatistics(context.Background(), filter, query)
 	if err != nil {
 		return fiber.NewError(
 			fiber.StatusInternalServerError,
@@ -53,7 +73,36 @@ func (s *ReportService) GetStatics(c *fiber.Ctx) error {
 		)
 	}
 
-	return c.JSON(stats)
+	return response.OK(c, "", stats)
+}
+
+// BuildStatistics: status dari PostgreSQL, lalu dihitung per tipe / tingkat / medali / periode di MongoDB
+func (s *ReportService) BuildStatistics(ctx context.Context, filter repository.StatisticsFilter, query model.StatisticsQuery) (*model.AchievementStatistics, error) {
+	statuses, err := s.Repo.GetReferenceStatuses(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	query.Statuses = statuses
+
+	return s.Achievements.AggregateStatistics(ctx, query)
+}
+
+// parseReportDate menerima YYYY-MM-DD atau RFC3339, string kosong berarti tanpa batas
+func parseReportDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
 }
 
 func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
@@ -72,12 +121,18 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 
 	switch userClaims.Role {
 
-	case "mahasiswa":
-		if userClaims.UserID != studentID {
+	case middleware.RoleStudent:
+		// :id adalah students.id, sedangkan claims berisi users.id
+		isOwner, err := s.Repo.IsOwnStudent(
+			context.Background(),
+			userClaims.UserID,
+			studentID,
+		)
+		if err != nil || !isOwner {
 			return fiber.NewError(fiber.StatusForbidden, "Access denied")
 		}
 
-	case "lecturer":
+	case middleware.RoleLecturer:
 		isAdvisor, err := s.Repo.IsAdvisor(
 			context.Background(),
 			userClaims.UserID,
@@ -87,17 +142,14 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 			return fiber.NewError(fiber.StatusForbidden, "Access denied")
 		}
 
-	case "admin":
+	case middleware.RoleAdmin:
 		// allow
 
 	default:
 		return fiber.NewError(fiber.StatusForbidden, "Access denied")
 	}
 
-	report, err := s.Repo.GetStudentReport(
-		context.Background(),
-		studentID,
-	)
+	report, err := s.BuildStudentReport(context.Background(), studentID)
 	if err != nil {
 		return fiber.NewError(
 			fiber.StatusInternalServerError,
@@ -105,6 +157,185 @@ func (s *ReportService) GetStudentReport(c *fiber.Ctx) error {
 		)
 	}
 
-	return c.JSON(report)
+	return response.OK(c, "", report)
+
+}
+
+// BuildStudentReport mengambil status dari PostgreSQL lalu melengkapi judul, tipe,
+// poin dan detail lomba dari MongoDB dengan satu query $in
+func (s *ReportService) BuildStudentReport(ctx context.Context, studentID string) (*model.StudentReport, error) {
+	report, err := s.Repo.GetStudentReport(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(report.Achievements))
+	for _, item := range report.Achievements {
+		ids = append(ids, item.MongoAchievementID)
+	}
+
+	achievements, err := s.Achievements.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*model.Achievement, len(achievements))
+	for i := range achievements {
+		byID[achievements[i].ID] = &achievements[i]
+	}
+
+	report.TotalPoints = 0
+	for i := range report.Achievements {
+		item := &report.Achievements[i]
+
+		a, ok := byID[item.MongoAchievementID]
+		if !ok {
+			item.Missing = true
+			continue
+		}
+
+		details := a.Details
+		item.Title = a.Title
+		item.AchievementType = a.AchievementType
+		item.Points = a.Points
+		item.Tags = a.Tags
+		item.Details = &details
+
+		if item.Status == model.StatusVerified {
+			report.TotalPoints += a.Points
+		}
+	}
+
+	if report.Achievements == nil {
+		report.Achievements = []model.StudentAchievementItem{}
+	}
+
+	return report, nil
+}
+
+// GetLeaderboard: GET /api/reports/leaderboard?program_study=&academic_year=&type=&from=&to=&page=&limit=
+// Mahasiswa hanya melihat identitasnya sendiri, peringkat lain dianonimkan.
+func (s *ReportService) GetLeaderboard(c *fiber.Ctx) error {
+	userClaims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	filter := model.LeaderboardFilter{
+		ProgramStudy:    c.Query("program_study"),
+		AcademicYear:    c.Query("academic_year"),
+		AchievementType: c.Query("type"),
+		Page:            c.QueryInt("page", 1),
+		Limit:           c.QueryInt("limit", 20),
+	}
+
+	if filter.Page < 1 {
+		return fiber.NewError(fiber.StatusBadRequest, "page must be at least 1")
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+
+	var err error
+	if filter.From, err = parseReportDate(c.Query("from")); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid 'from' date, use YYYY-MM-DD")
+	}
+	if filter.To, err = parseReportDate(c.Query("to")); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid 'to' date, use YYYY-MM-DD")
+	}
+
+	anonymize := userClaims.Role == middleware.RoleStudent
+
+	board, err := s.BuildLeaderboard(context.Background(), filter, userClaims.UserID, anonymize)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to build leaderboard")
+	}
+
+	return response.OK(c, "", board)
+}
+
+// BuildLeaderboard: prestasi verified dari PostgreSQL, poin dijumlahkan di MongoDB.
+// Poin sama mendapat peringkat sama (1, 2, 2, 4), urutan di dalamnya berdasarkan nama.
+func (s *ReportService) BuildLeaderboard(ctx context.Context, filter model.LeaderboardFilter, viewerUserID string, anonymize bool) (*model.Leaderboard, error) {
+	candidates, err := s.Repo.GetLeaderboardCandidates(ctx, filter.ProgramStudy, filter.AcademicYear)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(candidates))
+	students := map[string]model.LeaderboardCandidate{}
+	for _, c := range candidates {
+		ids = append(ids, c.MongoAchievementID)
+		students[c.UserID] = c
+	}
+
+	points, err := s.Achievements.SumPointsByStudent(ctx, ids, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]model.LeaderboardEntry, 0, len(points))
+	for _, p := range points {
+		student, ok := students[p.UserID]
+		if !ok {
+			continue
+		}
+
+		entries = append(entries, model.LeaderboardEntry{
+			UserID:       p.UserID,
+			StudentID:    student.StudentID,
+			FullName:     student.FullName,
+			ProgramStudy: student.ProgramStudy,
+			AcademicYear: student.AcademicYear,
+			Points:       p.Points,
+			Achievements: p.Achievements,
+		})
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		if entries[a].Points != entries[b].Points {
+			return entries[a].Points > entries[b].Points
+		}
+		if entries[a].FullName != entries[b].FullName {
+			return entries[a].FullName < entries[b].FullName
+		}
+		return entries[a].StudentID < entries[b].StudentID
+	})
+
+	board := &model.Leaderboard{
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: len(entries),
+		Data:  []model.LeaderboardEntry{},
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+
+		entry.Rank = i + 1
+		if i > 0 && entry.Points == entries[i-1].Points {
+			entry.Rank = entries[i-1].Rank
+		}
+
+		entry.IsMe = entry.UserID == viewerUserID
+		if entry.IsMe {
+			me := *entry
+			board.Me = &me
+		} else if anonymize {
+			entry.StudentID = ""
+			entry.FullName = ""
+			entry.Anonymized = true
+		}
+	}
+
+	start := (filter.Page - 1) * filter.Limit
+	if start < len(entries) {
+		end := start + filter.Limit
+		if end > len(entries) {
+			end = len(entries)
+		}
+		board.Data = entries[start:end]
+	}
 
+	return board, nil
 }
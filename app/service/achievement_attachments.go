@@ -0,0 +1,377 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/response"
+	"PROJECTUAS_BE/app/signing"
+	"PROJECTUAS_BE/app/storage"
+	"PROJECTUAS_BE/middleware"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	attachmentDownloadPurpose = "attachment-download"
+	signedURLTTL              = 15 * time.Minute
+)
+
+var errRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// attachmentPath: URL download lampiran yang butuh login
+func attachmentPath(achievementID string, attachmentID string) string {
+	return "/api/achievements/" + url.PathEscape(achievementID) + "/attachments/" + url.PathEscape(attachmentID)
+}
+
+// newAttachmentKey: key BlobStore untuk file baru. Key tidak pernah dipakai ulang,
+// sehingga penggantian file tidak menimpa file lama yang mungkin masih sedang diunduh.
+func newAttachmentKey(file *InspectedFile) string {
+	return "achievements/" + uuid.New().String() + file.Ext
+}
+
+// storeAttachment menyimpan file ke BlobStore beserta preview-nya jika bisa dibuat.
+// ID dan FileUrl diisi pemanggil.
+func (s *AchievementService) storeAttachment(data []byte, inspected *InspectedFile, fileName string) (model.Attachment, error) {
+	key := newAttachmentKey(inspected)
+	if err := s.Blobs.Put(context.Background(), key, bytes.NewReader(data), inspected.Size, inspected.ContentType); err != nil {
+		return model.Attachment{}, err
+	}
+
+	attachment := model.Attachment{
+		FileName:   fileName,
+		FileType:   inspected.ContentType,
+		StorageKey: key,
+		Size:       inspected.Size,
+		SHA256:     inspected.SHA256,
+		UploadedAt: time.Now(),
+	}
+	attachment.PreviewKey = s.storePreview(key, inspected.ContentType, data)
+	attachment.HasPreview = attachment.PreviewKey != ""
+
+	return attachment, nil
+}
+
+// removeAttachmentFiles menghapus file lampiran dan preview-nya
+func (s *AchievementService) removeAttachmentFiles(a model.Attachment) {
+	s.removeBlob(a.Key())
+	if a.PreviewKey != "" {
+		s.removeBlob(a.PreviewKey)
+	}
+}
+
+// removeBlob menghapus file dari BlobStore. Kegagalan hanya dicatat;
+// file yang tertinggal akan dihapus AttachmentGC.
+func (s *AchievementService) removeBlob(key string) {
+	if err := s.Blobs.Delete(context.Background(), key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Println("failed to remove attachment file", key, err)
+	}
+}
+
+// signedAttachmentURL membuat URL download tanpa login yang berlaku selama signedURLTTL
+func signedAttachmentURL(signer *signing.Signer, baseURL string, achievementID string, attachmentID string) (string, time.Time) {
+	expires := time.Now().Add(signedURLTTL).Truncate(time.Second)
+	sig := signer.Sign(attachmentDownloadPurpose, achievementID+"/"+attachmentID, expires)
+
+	return fmt.Sprintf("%s/public/attachments/%s/%s?exp=%d&sig=%s",
+		baseURL, url.PathEscape(achievementID), url.PathEscape(attachmentID), expires.Unix(), sig), expires
+}
+
+// DownloadAttachment: GET /api/achievements/:id/attachments/:attachmentId
+// Mendukung header Range agar PDF besar bisa dibuka sebagian.
+func (s *AchievementService) DownloadAttachment(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	achievement, attachment, err := s.findAttachment(c.Params("id"), c.Params("attachmentId"))
+	if err != nil {
+		return err
+	}
+
+	if err := s.canView(context.Background(), claims, achievement.StudentID); err != nil {
+		return err
+	}
+
+	return s.sendAttachment(c, attachment)
+}
+
+// GetAttachmentURL: GET /api/achievements/:id/attachments/:attachmentId/url
+// URL bertanda tangan untuk dibuka di browser / viewer tanpa header Authorization.
+func (s *AchievementService) GetAttachmentURL(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	achievement, attachment, err := s.findAttachment(c.Params("id"), c.Params("attachmentId"))
+	if err != nil {
+		return err
+	}
+
+	if err := s.canView(context.Background(), claims, achievement.StudentID); err != nil {
+		return err
+	}
+
+	link, expires := signedAttachmentURL(s.Signer, c.BaseURL(), achievement.ID, attachment.StableID())
+
+	return response.OK(c, "", fiber.Map{
+		"url":        link,
+		"expires_at": expires,
+	})
+}
+
+// DownloadSignedAttachment: GET /public/attachments/:id/:attachmentId?exp=&sig=
+func (s *AchievementService) DownloadSignedAttachment(c *fiber.Ctx) error {
+	id, attachmentID := c.Params("id"), c.Params("attachmentId")
+
+	exp, err := strconv.ParseInt(c.Query("exp"), 10, 64)
+	if err != nil || !s.Signer.Verify(attachmentDownloadPurpose, id+"/"+attachmentID, time.Unix(exp, 0), c.Query("sig")) {
+		return fiber.NewError(fiber.StatusForbidden, "Download link is invalid or expired")
+	}
+
+	_, attachment, err := s.findAttachment(id, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	return s.sendAttachment(c, attachment)
+}
+
+// DeleteAttachment: DELETE /api/achievements/:id/attachments/:attachmentId
+func (s *AchievementService) DeleteAttachment(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	achievement, err := s.editableAchievement(claims, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	attachment, err := attachmentByID(achievement, c.Params("attachmentId"))
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.RemoveAttachment(context.Background(), achievement.ID, *attachment); err != nil {
+		return err
+	}
+
+	s.removeAttachmentFiles(*attachment)
+
+	return response.OK(c, "Attachment deleted successfully", nil)
+}
+
+// ReplaceAttachment: PUT /api/achievements/:id/attachments/:attachmentId (multipart "file")
+// File diganti tanpa mengubah id lampiran, sehingga URL lama tetap mengarah ke lampiran yang sama.
+func (s *AchievementService) ReplaceAttachment(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	achievement, err := s.editableAchievement(claims, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	old, err := attachmentByID(achievement, c.Params("attachmentId"))
+	if err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "File is required")
+	}
+
+	data, inspected, err := s.readAttachment(file)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range achievement.Attachments {
+		if existing.StableID() != old.StableID() && existing.SHA256 == inspected.SHA256 {
+			return fiber.NewError(fiber.StatusConflict, "This file is already attached to the achievement")
+		}
+	}
+
+	replacement, err := s.storeAttachment(data, inspected, file.Filename)
+	if err != nil {
+		return err
+	}
+	replacement.ID = old.StableID()
+	replacement.FileUrl = attachmentPath(achievement.ID, replacement.ID)
+
+	if err := s.Repo.ReplaceAttachment(context.Background(), achievement.ID, *old, replacement); err != nil {
+		s.removeAttachmentFiles(replacement)
+		return err
+	}
+
+	s.removeAttachmentFiles(*old)
+
+	return response.OK(c, "Attachment replaced successfully", replacement)
+}
+
+// editableAchievement memastikan prestasi milik pemanggil dan masih bisa diedit
+// (draft / rejected). Setelah verified, lampiran tidak bisa ditambah, diganti atau dihapus.
+func (s *AchievementService) editableAchievement(claims *middleware.Claims, achievementID string) (*model.Achievement, error) {
+	if achievementID == "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Achievement ID is required")
+	}
+
+	achievement, err := s.Repo.FindById(context.Background(), achievementID)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Achievement not found")
+	}
+
+	if achievement.StudentID != claims.UserID {
+		return nil, fiber.NewError(fiber.StatusForbidden, "You can only change attachments of your own achievement")
+	}
+
+	if _, _, err := s.Lifecycle.Guard(context.Background(), achievementID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	return achievement, nil
+}
+
+func (s *AchievementService) findAttachment(achievementID string, attachmentID string) (*model.Achievement, *model.Attachment, error) {
+	achievement, err := s.Repo.GetAchievementByID(achievementID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if achievement == nil {
+		return nil, nil, fiber.NewError(fiber.StatusNotFound, "Achievement not found")
+	}
+
+	attachment, err := attachmentByID(achievement, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return achievement, attachment, nil
+}
+
+func attachmentByID(achievement *model.Achievement, attachmentID string) (*model.Attachment, error) {
+	for i := range achievement.Attachments {
+		if achievement.Attachments[i].StableID() == attachmentID {
+			return &achievement.Attachments[i], nil
+		}
+	}
+
+	return nil, fiber.NewError(fiber.StatusNotFound, "Attachment not found")
+}
+
+// sendAttachment men-stream file dari BlobStore, seluruhnya atau satu rentang byte (206)
+func (s *AchievementService) sendAttachment(c *fiber.Ctx, attachment *model.Attachment) error {
+	obj, err := s.Blobs.Open(context.Background(), attachment.Key())
+	if errors.Is(err, storage.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Attachment file not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	contentType := attachment.FileType
+	if contentType == "" {
+		contentType = obj.ContentType
+	}
+	if contentType == "" {
+		contentType = fiber.MIMEOctetStream
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName}))
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	if attachment.SHA256 != "" {
+		c.Set(fiber.HeaderETag, `"`+attachment.SHA256+`"`)
+	}
+	if !obj.ModTime.IsZero() {
+		c.Set(fiber.HeaderLastModified, obj.ModTime.UTC().Format(time.RFC1123))
+	}
+
+	start, length, ranged, err := byteRange(c.Get(fiber.HeaderRange), obj.Size)
+	if err != nil {
+		obj.Body.Close()
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", obj.Size))
+		return fiber.NewError(fiber.StatusRequestedRangeNotSatisfiable, "Requested range not satisfiable")
+	}
+
+	if ranged {
+		if _, err := obj.Body.Seek(start, io.SeekStart); err != nil {
+			obj.Body.Close()
+			return err
+		}
+		c.Status(fiber.StatusPartialContent)
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, obj.Size))
+	}
+
+	// fasthttp menutup body setelah response terkirim
+	c.Context().SetBodyStream(struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(obj.Body, length), obj.Body}, int(length))
+
+	return nil
+}
+
+// byteRange membaca header Range dengan satu rentang ("bytes=0-99", "bytes=100-", "bytes=-500").
+// ranged=false berarti seluruh file dikirim: tanpa Range, multi-range atau format tidak dikenal.
+func byteRange(header string, size int64) (start int64, length int64, ranged bool, err error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, size, false, nil
+	}
+
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, size, false, nil
+	}
+
+	// suffix range: n byte terakhir
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, size, false, nil
+		}
+		if n == 0 {
+			return 0, 0, false, errRangeNotSatisfiable
+		}
+		n = min(n, size)
+		return size - n, n, true, nil
+	}
+
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, size, false, nil
+	}
+	if start >= size {
+		return 0, 0, false, errRangeNotSatisfiable
+	}
+
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, size, false, nil
+		}
+		end = min(end, size-1)
+	}
+
+	return start, end - start + 1, true, nil
+}
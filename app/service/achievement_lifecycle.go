@@ -0,0 +1,272 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Aksi yang bisa dilakukan terhadap prestasi
+const (
+	ActionEdit   = "edit" // update konten atau upload attachment
+	ActionSubmit = "submit"
+	ActionVerify = "verify"
+	ActionReject = "reject"
+	ActionDelete = "delete"
+)
+
+// achievementTransitions: status asal → aksi → status tujuan.
+//
+//	draft ──submit──▶ submitted ──verify──▶ verified
+//	                      │
+//	                   reject
+//	                      ▼
+//	                  rejected ──submit (resubmit)──▶ submitted
+//
+// draft dan rejected masih bisa diedit dan dihapus (→ deleted).
+// verified dan deleted adalah status akhir.
+var achievementTransitions = map[string]map[string]string{
+	model.StatusDraft: {
+		ActionEdit:   model.StatusDraft,
+		ActionSubmit: model.StatusSubmitted,
+		ActionDelete: model.StatusDeleted,
+	},
+	model.StatusSubmitted: {
+		ActionVerify: model.StatusVerified,
+		ActionReject: model.StatusRejected,
+	},
+	model.StatusRejected: {
+		ActionEdit:   model.StatusRejected,
+		ActionSubmit: model.StatusSubmitted,
+		ActionDelete: model.StatusDeleted,
+	},
+	model.StatusVerified: {},
+	model.StatusDeleted:  {},
+}
+
+// AchievementLifecycle menegakkan state machine prestasi.
+// Semua perubahan status (submit, verify, reject, delete) harus lewat sini.
+type AchievementLifecycle struct {
+	Refs     repository.AchievementReferenceRepository
+	Students repository.StudentRepository
+}
+
+func NewAchievementLifecycle(refs repository.AchievementReferenceRepository, students repository.StudentRepository) *AchievementLifecycle {
+	return &AchievementLifecycle{Refs: refs, Students: students}
+}
+
+// Current mengembalikan reference terbaru dan statusnya.
+// Prestasi lama yang belum punya reference dianggap draft.
+func (l *AchievementLifecycle) Current(ctx context.Context, mongoAchievementID string) (*model.AchievementReference, string, error) {
+	ref, err := l.Refs.FindByMongoID(ctx, mongoAchievementID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, model.StatusDraft, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return ref, ref.Status, nil
+}
+
+// Guard memastikan aksi boleh dilakukan pada status saat ini, jika tidak → 409
+func (l *AchievementLifecycle) Guard(ctx context.Context, mongoAchievementID string, action string) (*model.AchievementReference, string, error) {
+	ref, status, err := l.Current(ctx, mongoAchievementID)
+	if err != nil {
+		return nil, "", fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch achievement status")
+	}
+
+	next, ok := achievementTransitions[status][action]
+	if !ok {
+		return nil, "", transitionConflict(action, status)
+	}
+
+	return ref, next, nil
+}
+
+// CreateDraft mencatat prestasi baru sebagai draft
+func (l *AchievementLifecycle) CreateDraft(ctx context.Context, mongoAchievementID string, userID string) error {
+	return l.RestoreDraft(ctx, mongoAchievementID, userID, userID)
+}
+
+// RestoreDraft membuat reference draft untuk dokumen milik ownerUserID.
+// Dipakai juga oleh consistency check untuk dokumen Mongo yang kehilangan reference.
+func (l *AchievementLifecycle) RestoreDraft(ctx context.Context, mongoAchievementID string, ownerUserID string, actorID string) error {
+	studentID, err := l.Students.GetStudentIDByUserID(ctx, ownerUserID)
+	if err != nil {
+		return err
+	}
+
+	return l.Refs.Create(ctx, &model.AchievementReference{
+		ID:                 uuid.New().String(),
+		StudentID:          studentID,
+		MongoAchievementID: mongoAchievementID,
+		Status:             model.StatusDraft,
+	}, actorID)
+}
+
+// ForceDelete menandai reference sebagai deleted tanpa melihat tabel transisi.
+// Hanya untuk perbaikan data oleh admin (dokumen Mongo-nya sudah tidak ada).
+func (l *AchievementLifecycle) ForceDelete(ctx context.Context, ref *model.AchievementReference, actorID string, note string) error {
+	return l.Refs.UpdateStatus(ctx, model.StatusTransition{
+		MongoAchievementID: ref.MongoAchievementID,
+		From:               ref.Status,
+		To:                 model.StatusDeleted,
+		ActorID:            actorID,
+		Note:               &note,
+	})
+}
+
+// Apply menjalankan aksi dan menyimpan status baru
+func (l *AchievementLifecycle) Apply(ctx context.Context, mongoAchievementID string, action string, actorID string, note *string) (*model.AchievementReference, error) {
+	return l.apply(ctx, mongoAchievementID, action, actorID, note, nil, time.Time{})
+}
+
+// Decide menjalankan verify / reject satu prestasi oleh dosen, beserta poin hasil rubrik.
+// From diambil dari status saat ini; dosen wali dan claim dicek di UPDATE yang sama.
+func (l *AchievementLifecycle) Decide(ctx context.Context, actorID string, d Decision) (*model.AchievementReference, error) {
+	return l.apply(ctx, d.MongoAchievementID, d.Action, actorID, d.Note, d.Points, d.ClaimedBefore)
+}
+
+// Decision adalah satu aksi verify / reject. From adalah status yang dilihat pemanggil;
+// jika status di database sudah berbeda, item tersebut gagal dengan ErrStatusChanged.
+type Decision struct {
+	MongoAchievementID string
+	From               string
+	Action             string
+	Note               *string
+	Points             *int
+	ClaimedBefore      time.Time // claim dosen lain sebelum waktu ini dianggap kedaluwarsa
+}
+
+// ApplyBatch menjalankan banyak aksi oleh actorID dalam satu transaksi database.
+// Hasil per item ada di slice error dengan urutan yang sama dengan decisions;
+// error kedua berarti transaksi gagal dan tidak ada yang tersimpan.
+func (l *AchievementLifecycle) ApplyBatch(ctx context.Context, actorID string, decisions []Decision) ([]error, error) {
+	results := make([]error, len(decisions))
+
+	var (
+		transitions []model.StatusTransition
+		index       []int // transitions[j] berasal dari decisions[index[j]]
+	)
+	for i, d := range decisions {
+		next, ok := achievementTransitions[d.From][d.Action]
+		if !ok {
+			results[i] = transitionConflict(d.Action, d.From)
+			continue
+		}
+
+		transitions = append(transitions, model.StatusTransition{
+			MongoAchievementID: d.MongoAchievementID,
+			From:               d.From,
+			To:                 next,
+			ActorID:            actorID,
+			Note:               d.Note,
+			Points:             d.Points,
+			ClaimedBefore:      d.ClaimedBefore,
+		})
+		index = append(index, i)
+	}
+
+	if len(transitions) == 0 {
+		return results, nil
+	}
+
+	applied, err := l.Refs.ApplyTransitions(ctx, transitions)
+	if err != nil {
+		return nil, err
+	}
+	for j, err := range applied {
+		results[index[j]] = err
+	}
+
+	return results, nil
+}
+
+func (l *AchievementLifecycle) apply(ctx context.Context, mongoAchievementID string, action string, actorID string, note *string, points *int, claimedBefore time.Time) (*model.AchievementReference, error) {
+	ref, next, err := l.Guard(ctx, mongoAchievementID, action)
+	if err != nil {
+		return nil, err
+	}
+
+	// prestasi lama tanpa reference: submit pertama membuat reference baru,
+	// aksi lain (edit / delete draft) tidak perlu disimpan
+	if ref == nil {
+		if action != ActionSubmit {
+			return nil, nil
+		}
+
+		studentID, err := l.Students.GetStudentIDByUserID(ctx, actorID)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Student not found")
+		}
+
+		now := time.Now()
+		ref = &model.AchievementReference{
+			ID:                 uuid.New().String(),
+			StudentID:          studentID,
+			MongoAchievementID: mongoAchievementID,
+			Status:             next,
+			SubmittedAt:        &now,
+		}
+
+		if err := l.Refs.Create(ctx, ref, actorID); err != nil {
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to save achievement status")
+		}
+
+		return ref, nil
+	}
+
+	from := ref.Status
+	if from == next {
+		return ref, nil
+	}
+
+	err = l.Refs.UpdateStatus(ctx, model.StatusTransition{
+		MongoAchievementID: mongoAchievementID,
+		From:               from,
+		To:                 next,
+		ActorID:            actorID,
+		Note:               note,
+		Points:             points,
+		ClaimedBefore:      claimedBefore,
+	})
+	var domainErr *repository.DomainError
+	if errors.As(err, &domainErr) {
+		// status sudah diubah request lain di antara Guard dan UpdateStatus, verify / reject
+		// oleh dosen yang bukan dosen wali, atau prestasi di-claim dosen lain
+		return nil, err
+	}
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to save achievement status")
+	}
+
+	now := time.Now()
+	ref.Status = next
+	ref.UpdatedAt = now
+
+	switch next {
+	case model.StatusSubmitted:
+		ref.SubmittedAt = &now
+		ref.VerifiedAt, ref.VerifiedBy, ref.RejectionNote = nil, nil, nil
+	case model.StatusVerified, model.StatusRejected:
+		ref.VerifiedAt = &now
+		ref.VerifiedBy = &actorID
+		ref.RejectionNote = note
+	}
+
+	return ref, nil
+}
+
+func transitionConflict(action string, status string) error {
+	return fiber.NewError(
+		fiber.StatusConflict,
+		fmt.Sprintf("Cannot %s achievement with status '%s'", action, status),
+	)
+}
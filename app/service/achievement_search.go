@@ -0,0 +1,165 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/response"
+	"PROJECTUAS_BE/middleware"
+	"context"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// panjang konteks (byte) di kiri dan kanan kata yang cocok pada snippet
+const snippetRadius = 60
+
+// SearchAchievements: GET /api/achievements/search?q=&limit=&page=
+// Sintaks q mengikuti $text MongoDB: "frasa persis" dan -kata untuk mengecualikan.
+// Mahasiswa hanya melihat prestasinya sendiri, dosen prestasi mahasiswa bimbingannya, admin semua.
+func (s *AchievementService) SearchAchievements(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	text := strings.TrimSpace(c.Query("q"))
+	if text == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "q is required")
+	}
+	if len(text) > 200 {
+		return fiber.NewError(fiber.StatusBadRequest, "q must be at most 200 characters")
+	}
+
+	query, err := parseListQuery(c, "")
+	if err != nil {
+		return err
+	}
+	if query.Cursor != "" || query.Sort != "" {
+		return fiber.NewError(fiber.StatusBadRequest, "search results are ordered by relevance, use page instead of cursor or sort")
+	}
+
+	ctx := context.Background()
+	search := model.AchievementSearch{Text: text}
+	if search.Owners, err = s.visibleOwners(ctx, claims); err != nil {
+		return err
+	}
+
+	hits, page, err := s.Repo.Search(ctx, search, query)
+	if err != nil {
+		return err
+	}
+
+	for i := range hits {
+		hits[i].Highlights = BuildHighlights(&hits[i].Achievement, text)
+	}
+
+	return response.Page(c, hits, page)
+}
+
+// BuildHighlights membuat snippet untuk setiap field yang memuat kata dari q,
+// urutannya sama dengan bobot text index (title paling relevan)
+func BuildHighlights(a *model.Achievement, q string) []model.SearchHighlight {
+	highlights := []model.SearchHighlight{}
+
+	pattern := searchPattern(q)
+	if pattern == nil {
+		return highlights
+	}
+
+	competition := a.Details.Competition()
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"title", a.Title},
+		{"details.competitionName", competition.CompetitionName},
+		{"tags", strings.Join(a.Tags, ", ")},
+		{"details.organizer", competition.Organizer},
+		{"description", a.Description},
+	}
+
+	for _, f := range fields {
+		if snippet, ok := highlightSnippet(f.value, pattern); ok {
+			highlights = append(highlights, model.SearchHighlight{Field: f.name, Snippet: snippet})
+		}
+	}
+
+	return highlights
+}
+
+// searchPattern mengubah q menjadi regexp case-insensitive untuk frasa dan kata positif.
+// Kata yang lebih panjang didahulukan agar "web" tidak memotong "website".
+func searchPattern(q string) *regexp.Regexp {
+	var terms []string
+
+	parts := strings.Split(q, `"`)
+	for i, part := range parts {
+		// bagian ganjil berada di dalam tanda kutip
+		if i%2 == 1 {
+			if phrase := strings.TrimSpace(part); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			if !strings.HasPrefix(word, "-") {
+				terms = append(terms, word)
+			}
+		}
+	}
+
+	if len(terms) == 0 {
+		return nil
+	}
+
+	sort.Slice(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+	for i, term := range terms {
+		terms[i] = regexp.QuoteMeta(term)
+	}
+
+	return regexp.MustCompile(`(?i)` + strings.Join(terms, "|"))
+}
+
+// highlightSnippet memotong value di sekitar kecocokan pertama dan membungkus
+// setiap kecocokan dengan <mark>. Teks lain di-escape.
+func highlightSnippet(value string, pattern *regexp.Regexp) (string, bool) {
+	first := pattern.FindStringIndex(value)
+	if first == nil {
+		return "", false
+	}
+
+	start := max(first[0]-snippetRadius, 0)
+	for start > 0 && !utf8.RuneStart(value[start]) {
+		start--
+	}
+	end := min(first[1]+snippetRadius, len(value))
+	for end < len(value) && !utf8.RuneStart(value[end]) {
+		end++
+	}
+	window := value[start:end]
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+
+	last := 0
+	for _, m := range pattern.FindAllStringIndex(window, -1) {
+		b.WriteString(html.EscapeString(window[last:m[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(window[m[0]:m[1]]))
+		b.WriteString("</mark>")
+		last = m[1]
+	}
+	b.WriteString(html.EscapeString(window[last:]))
+
+	if end < len(value) {
+		b.WriteString("…")
+	}
+
+	return b.String(), true
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/response"
+	"PROJECTUAS_BE/middleware"
+	"context"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// visibleOwners mengembalikan users.id pemilik prestasi yang boleh dilihat pemanggil:
+// mahasiswa → dirinya sendiri, dosen → mahasiswa bimbingan (students.advisor_id), admin → nil (semua)
+func (l *AchievementLifecycle) visibleOwners(ctx context.Context, claims *middleware.Claims) ([]string, error) {
+	switch claims.Role {
+	case middleware.RoleStudent:
+		return []string{claims.UserID}, nil
+
+	case middleware.RoleLecturer:
+		return l.Students.GetAdviseeUserIDs(ctx, claims.UserID)
+
+	case middleware.RoleAdmin:
+		return nil, nil
+	}
+
+	return nil, fiber.NewError(fiber.StatusForbidden, "Access denied")
+}
+
+// canView memastikan pemanggil boleh melihat prestasi milik ownerID (users.id).
+// Dipakai AchievementService dan LecturesService supaya aturannya sama.
+func (l *AchievementLifecycle) canView(ctx context.Context, claims *middleware.Claims, ownerID string) error {
+	owners, err := l.visibleOwners(ctx, claims)
+	if err != nil {
+		return err
+	}
+
+	if owners != nil && !slices.Contains(owners, ownerID) {
+		return fiber.NewError(fiber.StatusForbidden, "Access denied")
+	}
+	return nil
+}
+
+func (s *AchievementService) visibleOwners(ctx context.Context, claims *middleware.Claims) ([]string, error) {
+	return s.Lifecycle.visibleOwners(ctx, claims)
+}
+
+func (s *AchievementService) canView(ctx context.Context, claims *middleware.Claims, ownerID string) error {
+	return s.Lifecycle.canView(ctx, claims, ownerID)
+}
+
+// SetAchievementVisibility: PUT /api/student/achievements/:id/visibility {"is_public": true}
+// Mahasiswa memilih prestasi verified miliknya yang tampil di portfolio publik.
+func (s *AchievementService) SetAchievementVisibility(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	id := c.Params("id")
+
+	var req model.AchievementVisibilityRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	achievement, err := s.Repo.GetAchievementByID(id)
+	if err != nil {
+		return err
+	}
+	if achievement == nil {
+		return fiber.NewError(fiber.StatusNotFound, "Achievement not found")
+	}
+	if achievement.StudentID != claims.UserID {
+		return fiber.NewError(fiber.StatusForbidden, "You can only change your own achievement")
+	}
+
+	_, status, err := s.Lifecycle.Current(ctx, id)
+	if err != nil {
+		return err
+	}
+	if status != model.StatusVerified {
+		return fiber.NewError(fiber.StatusConflict, "Only verified achievements can be made public")
+	}
+
+	if err := s.Lifecycle.Refs.SetPublic(ctx, id, *req.IsPublic); err != nil {
+		return err
+	}
+
+	return response.OK(c, "Achievement visibility updated", fiber.Map{
+		"id":        id,
+		"is_public": *req.IsPublic,
+	})
+}
+
+// GetPublicAchievement: GET /public/achievements/:id tanpa login.
+// Prestasi yang tidak verified atau tidak dipublikasikan dianggap tidak ada.
+func (s *AchievementService) GetPublicAchievement(c *fiber.Ctx) error {
+	id := c.Params("id")
+	notFound := fiber.NewError(fiber.StatusNotFound, "Achievement not found")
+
+	ref, status, err := s.Lifecycle.Current(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	if ref == nil || status != model.StatusVerified || !ref.IsPublic {
+		return notFound
+	}
+
+	achievement, err := s.Repo.GetAchievementByID(id)
+	if err != nil {
+		return err
+	}
+	if achievement == nil {
+		return notFound
+	}
+
+	return response.OK(c, "", model.NewPublicAchievement(achievement, ref.VerifiedAt))
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/storage"
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// AttachmentGC menghapus file lampiran di BlobStore yang tidak lagi direferensikan
+// dokumen prestasi mana pun: sisa DeleteAchievement, penggantian file yang gagal dibersihkan, dll.
+// File yang lebih muda dari GracePeriod dilewati karena upload menyimpan file lebih dulu
+// sebelum metadata-nya masuk ke MongoDB.
+type AttachmentGC struct {
+	Achievements repository.AchievementRepository
+	Blobs        storage.BlobStore
+	Prefix       string
+	GracePeriod  time.Duration
+}
+
+// AttachmentGCReport: hasil satu kali Run
+type AttachmentGCReport struct {
+	Scanned int
+	Removed int
+	Failed  int
+}
+
+func NewAttachmentGC(achievements repository.AchievementRepository, blobs storage.BlobStore) *AttachmentGC {
+	return &AttachmentGC{
+		Achievements: achievements,
+		Blobs:        blobs,
+		Prefix:       "achievements/",
+		GracePeriod:  time.Hour,
+	}
+}
+
+// Start menjalankan GC secara berkala sampai ctx dibatalkan
+func (g *AttachmentGC) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report, err := g.Run(ctx)
+				if err != nil {
+					log.Println("attachment gc error:", err)
+					continue
+				}
+				if report.Removed > 0 || report.Failed > 0 {
+					log.Printf("attachment gc: %d removed, %d failed of %d files\n", report.Removed, report.Failed, report.Scanned)
+				}
+			}
+		}
+	}()
+}
+
+// Run membandingkan isi BlobStore dengan key yang direferensikan MongoDB.
+// Daftar file diambil lebih dulu sehingga file yang diupload setelahnya tidak ikut diperiksa.
+func (g *AttachmentGC) Run(ctx context.Context) (AttachmentGCReport, error) {
+	var report AttachmentGCReport
+
+	blobs, err := g.Blobs.List(ctx, g.Prefix)
+	if err != nil {
+		return report, err
+	}
+
+	referenced, err := g.Achievements.ListAttachmentKeys(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	cutoff := time.Now().Add(-g.GracePeriod)
+	for _, blob := range blobs {
+		report.Scanned++
+		if referenced[blob.Key] || blob.ModTime.After(cutoff) {
+			continue
+		}
+
+		if err := g.Blobs.Delete(ctx, blob.Key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Println("attachment gc: failed to remove", blob.Key, err)
+			report.Failed++
+			continue
+		}
+		report.Removed++
+	}
+
+	return report, nil
+}
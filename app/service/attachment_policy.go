@@ -0,0 +1,184 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"regexp"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AttachmentPolicy membatasi lampiran yang boleh diunggah per prestasi
+type AttachmentPolicy struct {
+	MaxSize  int64 // byte per file
+	MaxCount int   // lampiran per prestasi
+}
+
+func DefaultAttachmentPolicy() AttachmentPolicy {
+	return AttachmentPolicy{MaxSize: 5 << 20, MaxCount: 10}
+}
+
+// InspectedFile adalah hasil pemeriksaan isi file, bukan dari header request
+type InspectedFile struct {
+	ContentType string
+	Ext         string
+	Size        int64
+	SHA256      string
+}
+
+var (
+	pngMagic  = []byte("\x89PNG\r\n\x1a\n")
+	jpegMagic = []byte{0xFF, 0xD8, 0xFF}
+	pdfMagic  = []byte("%PDF-")
+
+	// penanda format lain di dalam file → kemungkinan polyglot
+	zipEndOfCentralDir = []byte("PK\x05\x06")
+	htmlMarker         = regexp.MustCompile(`(?i)<\s*(script|html|svg|iframe)`)
+
+	pdfNameEscape = regexp.MustCompile(`#([0-9A-Fa-f]{2})`)
+	// konten aktif dan enkripsi yang tidak boleh ada di PDF lampiran
+	pdfForbiddenName = regexp.MustCompile(`/(Encrypt|JavaScript|JS|Launch|EmbeddedFiles?|RichMedia|XFA)\b`)
+)
+
+// Inspect menentukan tipe file dari magic byte (PDF, PNG, JPEG) dan menolak file yang
+// strukturnya tidak utuh, dienkripsi, berisi konten aktif, atau membawa data format lain.
+func (p AttachmentPolicy) Inspect(data []byte) (*InspectedFile, error) {
+	if int64(len(data)) > p.MaxSize {
+		return nil, fiber.NewError(fiber.StatusRequestEntityTooLarge,
+			fmt.Sprintf("File exceeds the maximum size of %d bytes", p.MaxSize))
+	}
+	if len(data) == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "File is empty")
+	}
+
+	file := &InspectedFile{Size: int64(len(data))}
+
+	var err error
+	switch {
+	case bytes.HasPrefix(data, pdfMagic):
+		file.ContentType, file.Ext = "application/pdf", ".pdf"
+		err = checkPDF(data)
+	case bytes.HasPrefix(data, pngMagic):
+		file.ContentType, file.Ext = "image/png", ".png"
+		err = checkPNG(data)
+	case bytes.HasPrefix(data, jpegMagic):
+		file.ContentType, file.Ext = "image/jpeg", ".jpg"
+		err = checkJPEG(data)
+	default:
+		return nil, fiber.NewError(fiber.StatusUnsupportedMediaType, "Only PDF, PNG and JPEG files are allowed")
+	}
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusUnsupportedMediaType, "Rejected "+file.ContentType+" file: "+err.Error())
+	}
+
+	if bytes.Contains(data, zipEndOfCentralDir) || htmlMarker.Match(data[:min(len(data), 1024)]) {
+		return nil, fiber.NewError(fiber.StatusUnsupportedMediaType, "Rejected file: it also contains another file format")
+	}
+
+	sum := sha256.Sum256(data)
+	file.SHA256 = hex.EncodeToString(sum[:])
+
+	return file, nil
+}
+
+// readAttachment membaca file upload ke memori (dibatasi MaxSize) lalu memeriksanya
+func (s *AchievementService) readAttachment(file *multipart.FileHeader) ([]byte, *InspectedFile, error) {
+	if file.Size > s.Policy.MaxSize {
+		return nil, nil, fiber.NewError(fiber.StatusRequestEntityTooLarge,
+			fmt.Sprintf("File exceeds the maximum size of %d bytes", s.Policy.MaxSize))
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "Failed to read file")
+	}
+	defer src.Close()
+
+	// satu byte lebih agar file yang melebihi batas tetap terdeteksi oleh Inspect
+	data, err := io.ReadAll(io.LimitReader(src, s.Policy.MaxSize+1))
+	if err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "Failed to read file")
+	}
+
+	inspected, err := s.Policy.Inspect(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, inspected, nil
+}
+
+// checkPDF: tidak boleh ada data setelah %%EOF terakhir selain whitespace,
+// nama objek di-decode dulu (/J#61vaScript) sebelum dicek
+func checkPDF(data []byte) error {
+	eof := bytes.LastIndex(data, []byte("%%EOF"))
+	if eof < 0 {
+		return errors.New("missing %%EOF marker")
+	}
+	if len(bytes.TrimSpace(data[eof+len("%%EOF"):])) > 0 {
+		return errors.New("unexpected data after %%EOF")
+	}
+
+	names := pdfNameEscape.ReplaceAllFunc(data, func(m []byte) []byte {
+		b, _ := strconv.ParseUint(string(m[1:]), 16, 8)
+		return []byte{byte(b)}
+	})
+	if m := pdfForbiddenName.Find(names); m != nil {
+		if bytes.Equal(m, []byte("/Encrypt")) {
+			return errors.New("encrypted PDF")
+		}
+		return fmt.Errorf("PDF contains active content (%s)", m)
+	}
+
+	return nil
+}
+
+// checkPNG menelusuri chunk sampai IEND, yang harus menjadi akhir file
+func checkPNG(data []byte) error {
+	if _, err := png.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("invalid PNG: %v", err)
+	}
+
+	pos := len(pngMagic)
+	for pos+8 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		chunkType := string(data[pos+4 : pos+8])
+
+		end := pos + 8 + length + 4 // header + data + CRC
+		if end > len(data) {
+			return fmt.Errorf("truncated PNG chunk %q", chunkType)
+		}
+		if chunkType == "IEND" {
+			if end != len(data) {
+				return errors.New("unexpected data after IEND")
+			}
+			return nil
+		}
+		pos = end
+	}
+
+	return errors.New("missing IEND chunk")
+}
+
+// checkJPEG: file harus diakhiri marker EOI (FFD9), padding nol masih diterima
+func checkJPEG(data []byte) error {
+	if _, err := jpeg.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("invalid JPEG: %v", err)
+	}
+
+	trimmed := bytes.TrimRight(data, "\x00")
+	if !bytes.HasSuffix(trimmed, []byte{0xFF, 0xD9}) {
+		return errors.New("unexpected data after end of image")
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/preview"
+	"PROJECTUAS_BE/middleware"
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// storePreview membuat thumbnail dan menyimpannya di samping file aslinya
+// (achievements/<uuid>.pdf → achievements/<uuid>.preview.jpg).
+// Upload tetap berhasil tanpa preview, key kosong berarti preview tidak tersedia.
+func (s *AchievementService) storePreview(key string, contentType string, data []byte) string {
+	thumb, err := preview.Generate(contentType, data)
+	if err != nil {
+		if !errors.Is(err, preview.ErrUnavailable) {
+			log.Println("failed to generate attachment preview", key, err)
+		}
+		return ""
+	}
+
+	previewKey := strings.TrimSuffix(key, path.Ext(key)) + ".preview" + preview.Ext
+	if err := s.Blobs.Put(context.Background(), previewKey, bytes.NewReader(thumb), int64(len(thumb)), preview.ContentType); err != nil {
+		log.Println("failed to store attachment preview", previewKey, err)
+		return ""
+	}
+
+	return previewKey
+}
+
+// GetAttachmentPreview: GET /api/achievements/:id/attachments/:attachmentId/preview
+// Thumbnail JPEG agar dosen bisa melihat sertifikat tanpa mengunduh file aslinya.
+// Untuk PDF ini bukan render halaman: isinya gambar terbesar yang dipakai halaman pertama,
+// PDF yang halaman pertamanya hanya teks / vektor tidak punya preview (404).
+func (s *AchievementService) GetAttachmentPreview(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	achievement, attachment, err := s.findAttachment(c.Params("id"), c.Params("attachmentId"))
+	if err != nil {
+		return err
+	}
+
+	if err := s.canView(context.Background(), claims, achievement.StudentID); err != nil {
+		return err
+	}
+
+	if !attachment.HasPreview || attachment.PreviewKey == "" {
+		return fiber.NewError(fiber.StatusNotFound, "Preview is not available for this attachment")
+	}
+
+	return s.sendAttachment(c, &model.Attachment{
+		FileName:   strings.TrimSuffix(attachment.FileName, path.Ext(attachment.FileName)) + preview.Ext,
+		FileType:   preview.ContentType,
+		StorageKey: attachment.PreviewKey,
+	})
+}
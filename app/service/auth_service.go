@@ -1,76 +1,160 @@
 package service
 
 import (
+	model "PROJECTUAS_BE/app/Model"
 	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
 	"PROJECTUAS_BE/middleware"
+	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService struct {
-	repo repository.AuthRepository
+	repo        repository.AuthRepository
+	refreshRepo repository.RefreshTokenRepository
 }
 
-func NewAuthService(repo repository.AuthRepository) *AuthService {
-	return &AuthService{repo: repo}
+func NewAuthService(repo repository.AuthRepository, refreshRepo repository.RefreshTokenRepository) *AuthService {
+	return &AuthService{repo: repo, refreshRepo: refreshRepo}
 }
 
-func (s *AuthService) LoginService(email, password string) (string, error) {
+func (s *AuthService) LoginService(email, password string) (*model.LoginResponse, error) {
 
 	// Ambil user berdasarkan email
 	user, err := s.repo.FindByEmail(email)
 	if err != nil || user == nil {
-		return "", fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
 	}
 
 	// Cek password bcrypt
 	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
-		return "", fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password")
 	}
 
+	// Setiap login memulai family refresh token baru
+	familyID := uuid.New().String()
+	refreshToken, err := middleware.GenerateRefreshToken()
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to generate token")
+	}
+
+	err = s.refreshRepo.Create(context.Background(), &model.RefreshToken{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: middleware.HashRefreshToken(refreshToken),
+		ExpiresAt: time.Now().Add(middleware.RefreshTokenTTL()),
+	})
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to store refresh token")
+	}
+
+	return s.issueAccessToken(user, familyID, refreshToken)
+}
+
+// issueAccessToken membuat access token (JWT) untuk user dan family refresh token yang diberikan
+func (s *AuthService) issueAccessToken(user *model.User, familyID, refreshToken string) (*model.LoginResponse, error) {
 	// Ambil role user berdasarkan tabel role_permissions
 	role, err := s.repo.GetRoleByUserID(user.ID)
 	// role, err := s.Repo.GetRoleNameByRoleID(user.ID)
 	if err != nil {
-		return "", fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch user role")
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch user role")
 	}
 
-	// Generate JWT termasuk role
+	// Ambil permission milik role dari tabel role_permissions
+	permissions, err := s.repo.GetPermissionsByRole(role)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch role permissions")
+	}
+
+	// Generate JWT termasuk role dan permissions
 	token, err := middleware.GenerateToken(
 		user.ID,
 		user.Username,
 		user.Email,
-		role,       // <-- pastikan diisi role
-		[]string{}, // permissions jika diperlukan
+		role, // <-- pastikan diisi role
+		permissions,
+		familyID,
 	)
 
 	if err != nil {
-		return "", fiber.NewError(fiber.StatusInternalServerError, "Failed to generate token")
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to generate token")
 	}
 
-	return token, nil
+	return &model.LoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(middleware.AccessTokenTTL().Seconds()),
+	}, nil
 }
 
 func (s *AuthService) Login(c *fiber.Ctx) error {
 	var body struct {
-		Email    string `json:"email"`
-		Password string `json:"password_hash"`
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password_hash" validate:"required"`
 	}
 
-	if err := c.BodyParser(&body); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	if err := parseBody(c, &body); err != nil {
+		return err
 	}
 
 	// Panggil logic
-	token, err := s.LoginService(body.Email, body.Password)
+	tokens, err := s.LoginService(body.Email, body.Password)
+	if err != nil {
+		return err
+	}
+
+	return response.OK(c, "Login successful", tokens)
+}
+
+func (s *AuthService) Refresh(c *fiber.Ctx) error {
+	var body model.RefreshRequest
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	// Rotasi: refresh token lama tidak bisa dipakai lagi
+	newRefreshToken, err := middleware.GenerateRefreshToken()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to generate token")
+	}
+
+	old, err := s.refreshRepo.Rotate(
+		context.Background(),
+		middleware.HashRefreshToken(body.RefreshToken),
+		&model.RefreshToken{
+			ID:        uuid.New().String(),
+			TokenHash: middleware.HashRefreshToken(newRefreshToken),
+			ExpiresAt: time.Now().Add(middleware.RefreshTokenTTL()),
+		},
+	)
+
+	switch {
+	case errors.Is(err, repository.ErrRefreshTokenReused):
+		return fiber.NewError(fiber.StatusUnauthorized, "Refresh token reuse detected, session revoked")
+	case errors.Is(err, repository.ErrRefreshTokenNotFound), errors.Is(err, repository.ErrRefreshTokenExpired):
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid or expired refresh token")
+	case err != nil:
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to refresh token")
+	}
+
+	user, err := s.repo.GetProfile(old.UserID)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+		return fiber.NewError(fiber.StatusUnauthorized, "User not found")
 	}
 
-	return c.JSON(fiber.Map{"token": token})
+	tokens, err := s.issueAccessToken(user, old.FamilyID, newRefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return response.OK(c, "Token refreshed", tokens)
 }
 
 func (s *AuthService) GetProfile(c *fiber.Ctx) error {
@@ -81,40 +165,41 @@ func (s *AuthService) GetProfile(c *fiber.Ctx) error {
 	fmt.Println("DEBUG c.Locals(\"claims\"):", claimsData)
 
 	if claimsData == nil {
-		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	claims, ok := claimsData.(*middleware.Claims)
 	if !ok {
-		return c.Status(401).JSON(fiber.Map{"error": "Invalid token data"})
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid token data")
 	}
 
 	// Query database by user_id
 	user, err := s.repo.GetProfile(claims.UserID)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "User not found"})
+		return fiber.NewError(fiber.StatusNotFound, "User not found")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data": fiber.Map{
-			"username": user.Username,
-			"fullname": user.Fullname,
-			"email":    user.Email,
-		},
+	return response.OK(c, "", fiber.Map{
+		"username": user.Username,
+		"fullname": user.Fullname,
+		"email":    user.Email,
 	})
 }
 
 func (s *AuthService) Logout(c *fiber.Ctx) error {
-	authHeader := c.Get("Authorization")
-	tokenStr := strings.Split(authHeader, " ")[1]
-
 	claims := c.Locals("claims").(*middleware.Claims)
 
-	// masukkan token ke blacklist dengan expiry JWT
-	middleware.BlacklistToken(tokenStr, claims.ExpiresAt.Time)
+	// masukkan jti token ke revocation store dengan expiry JWT
+	if err := middleware.BlacklistToken(claims.ID, claims.ExpiresAt.Time); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to revoke token")
+	}
 
-	return c.JSON(fiber.Map{
-		"message": "Logout successful",
-	})
+	// refresh token dari sesi yang sama juga tidak boleh dipakai lagi
+	if claims.SessionID != "" {
+		if err := s.refreshRepo.RevokeFamily(context.Background(), claims.SessionID); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to revoke session")
+		}
+	}
+
+	return response.OK(c, "Logout successful", nil)
 }
@@ -0,0 +1,149 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
+	"PROJECTUAS_BE/middleware"
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConsistencyService mencocokkan dokumen prestasi di MongoDB dengan achievement_references
+// di PostgreSQL dan (opsional) memperbaiki yang tidak sinkron.
+type ConsistencyService struct {
+	Achievements repository.AchievementRepository
+	Lifecycle    *AchievementLifecycle
+}
+
+func NewConsistencyService(achievements repository.AchievementRepository, lifecycle *AchievementLifecycle) *ConsistencyService {
+	return &ConsistencyService{
+		Achievements: achievements,
+		Lifecycle:    lifecycle,
+	}
+}
+
+// Check: POST /api/admin/consistency/check?repair=true
+func (s *ConsistencyService) Check(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	repair := c.QueryBool("repair", false)
+
+	report, err := s.Run(context.Background(), repair, claims.UserID)
+	if err != nil {
+		log.Println("ERROR CONSISTENCY CHECK:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to run consistency check")
+	}
+
+	return response.OK(c, "", report)
+}
+
+// Run membandingkan kedua store. Tanpa repair hanya melaporkan, dengan repair:
+//   - missing_document  → reference ditandai deleted
+//   - missing_reference → reference draft dibuat ulang untuk pemilik dokumen
+//   - deleted_reference → dokumen Mongo dihapus (melanjutkan delete yang terputus)
+func (s *ConsistencyService) Run(ctx context.Context, repair bool, actorID string) (*model.ConsistencyReport, error) {
+	owners, err := s.Achievements.ListOwners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := s.Lifecycle.Refs.ListLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.ConsistencyReport{
+		CheckedAt:      time.Now(),
+		Repair:         repair,
+		MongoDocuments: len(owners),
+		References:     len(refs),
+		Issues:         []model.ConsistencyIssue{},
+	}
+
+	referenced := make(map[string]bool, len(refs))
+
+	for i := range refs {
+		ref := &refs[i]
+		referenced[ref.MongoAchievementID] = true
+
+		_, exists := owners[ref.MongoAchievementID]
+
+		switch {
+		case !exists && ref.Status != model.StatusDeleted:
+			issue := model.ConsistencyIssue{
+				Kind:               model.IssueMissingDocument,
+				MongoAchievementID: ref.MongoAchievementID,
+				StudentID:          ref.StudentID,
+				Status:             ref.Status,
+				Action:             "mark reference as deleted",
+			}
+			if repair {
+				err := s.Lifecycle.ForceDelete(ctx, ref, actorID, "consistency check: mongo document missing")
+				setRepairResult(&issue, err)
+			}
+			report.Issues = append(report.Issues, issue)
+
+		case exists && ref.Status == model.StatusDeleted:
+			issue := model.ConsistencyIssue{
+				Kind:               model.IssueDeletedReference,
+				MongoAchievementID: ref.MongoAchievementID,
+				StudentID:          ref.StudentID,
+				Status:             ref.Status,
+				Action:             "delete mongo document",
+			}
+			if repair {
+				err := s.Achievements.Delete(ctx, ref.MongoAchievementID)
+				setRepairResult(&issue, err)
+			}
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	// urutkan agar hasil laporan stabil
+	ids := make([]string, 0, len(owners))
+	for id := range owners {
+		if !referenced[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		issue := model.ConsistencyIssue{
+			Kind:               model.IssueMissingReference,
+			MongoAchievementID: id,
+			Action:             "create draft reference",
+		}
+		if repair {
+			err := s.Lifecycle.RestoreDraft(ctx, id, owners[id], actorID)
+			if errors.Is(err, repository.ErrNotFound) {
+				// pemilik dokumen tidak punya profil mahasiswa, perlu dicek manual
+				issue.Error = "student not found"
+			} else {
+				setRepairResult(&issue, err)
+			}
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	return report, nil
+}
+
+// error asli hanya ditulis ke log, report yang dikirim ke client berisi pesan umum
+func setRepairResult(issue *model.ConsistencyIssue, err error) {
+	if err != nil {
+		log.Printf("consistency repair %s (%s): %v\n", issue.MongoAchievementID, issue.Kind, err)
+		issue.Error = "repair failed, see server log"
+		return
+	}
+	issue.Repaired = true
+}
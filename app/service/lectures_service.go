@@ -3,20 +3,32 @@ package service
 import (
 	model "PROJECTUAS_BE/app/Model"
 	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
 	"PROJECTUAS_BE/middleware"
 	"context"
-	"database/sql"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type LecturesService struct {
-	Repo repository.LecturesRepository
+	Repo         repository.LecturesRepository
+	Lifecycle    *AchievementLifecycle
+	Achievements repository.AchievementRepository
+	Scoring      *ScoringService
+	ReviewSLA    time.Duration // batas waktu review sejak submit, lebih dari ini ditandai overdue
+	ClaimLease   time.Duration // lama claim review berlaku sebelum bisa diambil dosen lain
 }
 
-func NewLecturesService(repo repository.LecturesRepository) *LecturesService {
-	return &LecturesService{Repo: repo}
+func NewLecturesService(repo repository.LecturesRepository, lifecycle *AchievementLifecycle, achievements repository.AchievementRepository, scoring *ScoringService) *LecturesService {
+	return &LecturesService{
+		Repo:         repo,
+		Lifecycle:    lifecycle,
+		Achievements: achievements,
+		Scoring:      scoring,
+		ReviewSLA:    72 * time.Hour,
+		ClaimLease:   30 * time.Minute,
+	}
 }
 
 func (s *LecturesService) VerifyAchievement(c *fiber.Ctx) error {
@@ -26,12 +38,6 @@ func (s *LecturesService) VerifyAchievement(c *fiber.Ctx) error {
 	}
 
 	userClaims := claims.(*middleware.Claims)
-	if userClaims.Role != "dosen" {
-		return fiber.NewError(
-			fiber.StatusForbidden,
-			"Only dosen wali can verify achievements",
-		)
-	}
 
 	achievementID := c.Params("id")
 	if achievementID == "" {
@@ -42,62 +48,70 @@ func (s *LecturesService) VerifyAchievement(c *fiber.Ctx) error {
 	}
 
 	var req model.VerifyRequest
-	if err := c.BodyParser(&req); err != nil {
-		return fiber.NewError(
-			fiber.StatusBadRequest,
-			"Invalid request body",
-		)
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
-	if req.Status != "verified" && req.Status != "rejected" {
-		return fiber.NewError(
-			fiber.StatusBadRequest,
-			"Status must be 'verified' or 'rejected'",
-		)
-	}
+	// hanya prestasi berstatus submitted yang bisa diverifikasi / ditolak,
+	// oleh dosen wali dan tidak sedang di-claim dosen lain
+	decision := s.reviewDecision(achievementID, ActionVerify)
 
-	if req.Status == "rejected" && req.RejectionReason == nil {
-		return fiber.NewError(
-			fiber.StatusBadRequest,
-			"Rejection reason is required",
-		)
+	if req.Status == "rejected" {
+		decision.Action = ActionReject
+		decision.Note = req.RejectionReason
+	} else {
+		points, err := s.scoreAchievement(context.Background(), achievementID)
+		if err != nil {
+			return err
+		}
+		decision.Points = points
 	}
 
-	err := s.Repo.Verify(
-		context.Background(),
-		achievementID,
-		req.Status,
-		userClaims.UserID,
-		req.RejectionReason,
-	)
-
+	ref, err := s.Lifecycle.Decide(context.Background(), userClaims.UserID, decision)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return fiber.NewError(
-				fiber.StatusNotFound,
-				"Achievement not found or not in submitted status",
-			)
-		}
-		return fiber.NewError(
-			fiber.StatusInternalServerError,
-			err.Error(),
-		)
+		return err
 	}
 
 	// ===== 6. Response =====
-	return c.JSON(fiber.Map{
-		"message": "Achievement verification successful",
-		"data": fiber.Map{
-			"mongo_achievement_id": achievementID,
-			"status":               req.Status,
-			"verified_at":          time.Now(),
-			"verified_by":          userClaims.UserID,
-			"rejection_reason":     req.RejectionReason,
-		},
+	return response.OK(c, "Achievement verification successful", fiber.Map{
+		"mongo_achievement_id": achievementID,
+		"status":               ref.Status,
+		"verified_at":          ref.VerifiedAt,
+		"verified_by":          ref.VerifiedBy,
+		"rejection_reason":     ref.RejectionNote,
+		"points":               decision.Points,
 	})
 
 }
 
+// reviewDecision: keputusan dosen untuk satu prestasi dengan batas claim dari ClaimLease
+func (s *LecturesService) reviewDecision(achievementID string, action string) Decision {
+	return Decision{
+		MongoAchievementID: achievementID,
+		From:               model.StatusSubmitted,
+		Action:             action,
+		ClaimedBefore:      time.Now().Add(-s.ClaimLease),
+	}
+}
+
+// scoreAchievement menghitung poin dari rubrik, bukan dari nilai yang diisi mahasiswa
+func (s *LecturesService) scoreAchievement(ctx context.Context, achievementID string) (*int, error) {
+	achievement, err := s.Achievements.GetAchievementByID(achievementID)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch achievement")
+	}
+	if achievement == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Achievement not found")
+	}
+
+	points, err := s.Scoring.PointsFor(ctx, achievement)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to calculate points")
+	}
+
+	return &points, nil
+}
+
 func (s *LecturesService) RejectAchievement(c *fiber.Ctx) error {
 	claims := c.Locals("claims")
 	if claims == nil {
@@ -105,12 +119,6 @@ func (s *LecturesService) RejectAchievement(c *fiber.Ctx) error {
 	}
 
 	userClaims := claims.(*middleware.Claims)
-	if userClaims.Role != "dosen" {
-		return fiber.NewError(
-			fiber.StatusForbidden,
-			"Only dosen wali can verify achievements",
-		)
-	}
 
 	achievementID := c.Params("id")
 	if achievementID == "" {
@@ -121,41 +129,23 @@ func (s *LecturesService) RejectAchievement(c *fiber.Ctx) error {
 	}
 
 	var req model.RejectRequest
-	if err := c.BodyParser(&req); err != nil {
-		return fiber.NewError(
-			fiber.StatusBadRequest,
-			"Invalid request body",
-		)
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
-	err := s.Repo.Reject(
-		context.Background(),
-		achievementID,
-		req.Reason,
-		userClaims.UserID,
-	)
+	decision := s.reviewDecision(achievementID, ActionReject)
+	decision.Note = &req.Reason
 
+	_, err := s.Lifecycle.Decide(context.Background(), userClaims.UserID, decision)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return fiber.NewError(
-				fiber.StatusNotFound,
-				"Achievement not found or not in submitted status",
-			)
-		}
-		return fiber.NewError(
-			fiber.StatusInternalServerError,
-			err.Error(),
-		)
+		return err
 	}
 
 	// ===== 6. Response =====
-	return c.JSON(fiber.Map{
-		"message": "Achievement rejected successfully",
-		"data": fiber.Map{
-			"mongo_achievement_id": achievementID,
-			"status":               "rejected",
-			"reason":               req.Reason,
-		},
+	return response.OK(c, "Achievement rejected successfully", fiber.Map{
+		"mongo_achievement_id": achievementID,
+		"status":               "rejected",
+		"reason":               req.Reason,
 	})
 }
 
@@ -187,31 +177,36 @@ func (s *LecturesService) GetHistory(c *fiber.Ctx) error { // Get History bisa d
 		return fiber.NewError(fiber.StatusNotFound, "No history found")
 	}
 
-	if userClaims.Role == "mahasiswa" {
-		if histories[0].StudentID != userClaims.UserID {
-			return fiber.NewError(fiber.StatusForbidden, "Access denied")
-		}
+	// aturan akses sama dengan detail prestasi: mahasiswa pemilik, dosen wali, atau admin
+	if err := s.Lifecycle.canView(context.Background(), userClaims, histories[0].StudentUserID); err != nil {
+		return err
 	}
-	return c.JSON(fiber.Map{ // response
-		"message": "Achievement history fetched successfully",
-		"data":    histories,
+
+	latest := histories[len(histories)-1]
+
+	return response.OK(c, "Achievement history fetched successfully", fiber.Map{
+		"mongo_achievement_id": mongoAchievementID,
+		"current_status":       latest.ToStatus,
+		"total":                len(histories),
+		"timeline":             histories,
 	})
 }
 
 func (s *LecturesService) GetLectures(c *fiber.Ctx) error {
-	lecturers, err := s.Repo.GetallLectures(context.Background())
+	// ?department=&sort=full_name&limit=&page=|cursor=
+	query, err := parseListQuery(c, "full_name")
 	if err != nil {
-		return fiber.NewError(
-			fiber.StatusInternalServerError,
-			"Failed to fetch lecturers",
-		)
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"message": "Lecturers fetched successfully",
-		"total":   len(lecturers),
-		"data":    lecturers,
-	})
+	filter := model.LecturerFilter{Department: c.Query("department")}
+
+	lecturers, page, err := s.Repo.GetallLectures(context.Background(), filter, query)
+	if err != nil {
+		return err
+	}
+
+	return response.Page(c, lecturers, page)
 }
 
 func (s *LecturesService) Getadvisees(c *fiber.Ctx) error {
@@ -228,9 +223,9 @@ func (s *LecturesService) Getadvisees(c *fiber.Ctx) error {
 	}
 
 	switch userClaims.Role {
-	case "admin", "mahasiswa":
+	case middleware.RoleAdmin, middleware.RoleStudent:
 		// allowed
-	case "dosen":
+	case middleware.RoleLecturer:
 		// dosen hanya boleh lihat mahasiswa bimbingannya sendiri
 		if userClaims.UserID != lecturerID {
 			return fiber.NewError(fiber.StatusForbidden, "Access denied")
@@ -252,7 +247,7 @@ func (s *LecturesService) Getadvisees(c *fiber.Ctx) error {
 	}
 
 	// ===== 5. Response =====
-	return c.JSON(fiber.Map{
+	return response.OK(c, "", fiber.Map{
 		"lecturer_id": lecturerID,
 		"total":       len(students),
 		"students":    students,
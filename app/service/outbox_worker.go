@@ -0,0 +1,110 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// OutboxWorker menerapkan pesan achievement_outbox ke MongoDB.
+// Pesan yang gagal dicoba lagi dengan backoff sampai MaxAttempts.
+type OutboxWorker struct {
+	Outbox       repository.OutboxRepository
+	Achievements repository.AchievementRepository
+	BatchSize    int
+	Lease        time.Duration
+	MaxAttempts  int
+}
+
+func NewOutboxWorker(outbox repository.OutboxRepository, achievements repository.AchievementRepository) *OutboxWorker {
+	return &OutboxWorker{
+		Outbox:       outbox,
+		Achievements: achievements,
+		BatchSize:    50,
+		Lease:        time.Minute,
+		MaxAttempts:  10,
+	}
+}
+
+// Start menjalankan worker secara berkala sampai ctx dibatalkan
+func (w *OutboxWorker) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				processed, err := w.ProcessBatch(ctx)
+				if err != nil {
+					log.Println("outbox worker error:", err)
+					continue
+				}
+				if processed > 0 {
+					log.Printf("outbox worker: %d message applied\n", processed)
+				}
+			}
+		}
+	}()
+}
+
+// ProcessBatch mengambil satu batch pesan dan mengembalikan jumlah yang berhasil diterapkan
+func (w *OutboxWorker) ProcessBatch(ctx context.Context) (int, error) {
+	messages, err := w.Outbox.ClaimPending(ctx, w.BatchSize, w.Lease, w.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, m := range messages {
+		if err := w.apply(ctx, m); err != nil {
+			retryAt := time.Now().Add(outboxBackoff(m.Attempts))
+			if markErr := w.Outbox.MarkFailed(ctx, m.ID, err.Error(), retryAt); markErr != nil {
+				return processed, markErr
+			}
+			log.Printf("outbox worker: %s failed (attempt %d): %v\n", m.IdempotencyKey, m.Attempts, err)
+			continue
+		}
+
+		if err := w.Outbox.MarkProcessed(ctx, m.ID); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+func (w *OutboxWorker) apply(ctx context.Context, m model.OutboxMessage) error {
+	switch m.EventType {
+	case model.OutboxStatusSync:
+		var sync model.AchievementStatusSync
+		if err := json.Unmarshal(m.Payload, &sync); err != nil {
+			return err
+		}
+		return w.Achievements.ApplyStatusSync(ctx, m.MongoAchievementID, sync)
+
+	case model.OutboxPointsSync:
+		var sync model.AchievementPointsSync
+		if err := json.Unmarshal(m.Payload, &sync); err != nil {
+			return err
+		}
+		return w.Achievements.ApplyPointsSync(ctx, m.MongoAchievementID, sync)
+	}
+
+	return fmt.Errorf("unknown outbox event type %q", m.EventType)
+}
+
+// outboxBackoff: 2, 4, 8, ... detik, maksimal 10 menit
+func outboxBackoff(attempts int) time.Duration {
+	if attempts > 9 {
+		return 10 * time.Minute
+	}
+	return time.Duration(1<<attempts) * time.Second
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type PermissionService struct {
+	Repo repository.PermissionRepository
+}
+
+func NewPermissionService(repo repository.PermissionRepository) *PermissionService {
+	return &PermissionService{Repo: repo}
+}
+
+func (s *PermissionService) GetAllPermissions(c *fiber.Ctx) error {
+	permissions, err := s.Repo.GetAllPermissions(context.Background())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch permissions")
+	}
+
+	return response.OK(c, "", permissions)
+}
+
+func (s *PermissionService) GetRolePermissions(c *fiber.Ctx) error {
+	roleID := c.Params("id")
+	if _, err := uuid.Parse(roleID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid role id")
+	}
+
+	permissions, err := s.Repo.GetPermissionsByRoleID(context.Background(), roleID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch role permissions")
+	}
+
+	return response.OK(c, "", permissions)
+}
+
+// Permission baru berlaku di token yang diterbitkan setelah login / refresh berikutnya
+func (s *PermissionService) GrantPermission(c *fiber.Ctx) error {
+	roleID := c.Params("id")
+	if _, err := uuid.Parse(roleID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid role id")
+	}
+
+	var req model.RolePermissionRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	err := s.Repo.Grant(context.Background(), roleID, req.PermissionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to grant permission")
+	}
+
+	return response.Created(c, "Permission granted successfully", fiber.Map{
+		"role_id":       roleID,
+		"permission_id": req.PermissionID,
+	})
+}
+
+func (s *PermissionService) RevokePermission(c *fiber.Ctx) error {
+	roleID := c.Params("id")
+	if _, err := uuid.Parse(roleID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid role id")
+	}
+
+	permissionID := c.Params("permissionId")
+	if permissionID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Permission ID is required")
+	}
+
+	err := s.Repo.Revoke(context.Background(), roleID, permissionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Role does not have this permission")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to revoke permission")
+	}
+
+	return response.OK(c, "Permission revoked successfully", nil)
+}
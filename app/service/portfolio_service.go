@@ -0,0 +1,197 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
+	"PROJECTUAS_BE/app/signing"
+	"PROJECTUAS_BE/middleware"
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// purpose signature link verifikasi, lihat signing.Signer
+const verificationLinkPurpose = "achievement-verification"
+
+// PortfolioService melayani portfolio publik mahasiswa dan link verifikasi prestasi
+// yang bisa dibuka pihak ketiga (misalnya perusahaan) tanpa login.
+type PortfolioService struct {
+	Repo         repository.PortfolioRepository
+	Achievements repository.AchievementRepository
+	Signer       *signing.Signer
+}
+
+func NewPortfolioService(repo repository.PortfolioRepository, achievements repository.AchievementRepository, signer *signing.Signer) *PortfolioService {
+	return &PortfolioService{
+		Repo:         repo,
+		Achievements: achievements,
+		Signer:       signer,
+	}
+}
+
+// GetPortfolio: GET /public/portfolio/:username
+// Hanya prestasi verified yang dipublikasikan (is_public) oleh mahasiswa.
+func (s *PortfolioService) GetPortfolio(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	profile, err := s.Repo.GetProfile(ctx, c.Params("username"))
+	if err != nil {
+		return err
+	}
+
+	records, err := s.Repo.ListPublicVerified(ctx, profile.StudentID)
+	if err != nil {
+		return err
+	}
+
+	portfolio, err := s.BuildPortfolio(ctx, c.BaseURL(), profile, records)
+	if err != nil {
+		return err
+	}
+
+	return response.OK(c, "", portfolio)
+}
+
+// BuildPortfolio menggabungkan bukti verifikasi dari PostgreSQL dengan konten dari MongoDB.
+// Dokumen milik mahasiswa lain atau yang sudah hilang dari MongoDB dilewati.
+func (s *PortfolioService) BuildPortfolio(ctx context.Context, baseURL string, profile *model.PortfolioProfile, records []model.VerificationRecord) (*model.Portfolio, error) {
+	portfolio := &model.Portfolio{
+		Student:      *profile,
+		Achievements: []model.PortfolioAchievement{},
+	}
+
+	ids := make([]string, 0, len(records))
+	for _, rec := range records {
+		ids = append(ids, rec.MongoAchievementID)
+	}
+
+	docs, err := s.Achievements.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*model.Achievement, len(docs))
+	for i := range docs {
+		byID[docs[i].ID] = &docs[i]
+	}
+
+	// records sudah terurut verified_at terbaru
+	for _, rec := range records {
+		doc, ok := byID[rec.MongoAchievementID]
+		if !ok || doc.StudentID != profile.UserID {
+			continue
+		}
+
+		verifiedAt := rec.VerifiedAt
+		item := model.PortfolioAchievement{
+			PublicAchievement: model.NewPublicAchievement(doc, &verifiedAt),
+			Attachments:       make([]model.Attachment, 0, len(doc.Attachments)),
+			VerifiedBy:        rec.VerifierName,
+			VerificationURL:   s.VerificationURL(baseURL, doc.ID),
+		}
+		// pengunjung tidak login, lampiran dibuka lewat signed URL berumur pendek
+		for _, a := range doc.Attachments {
+			a.FileUrl, _ = signedAttachmentURL(s.Signer, baseURL, doc.ID, a.StableID())
+			item.Attachments = append(item.Attachments, a)
+		}
+		// poin di PostgreSQL yang berlaku, dokumen MongoDB bisa tertinggal outbox
+		if rec.Points != nil {
+			item.Points = *rec.Points
+		}
+
+		portfolio.TotalPoints += item.Points
+		portfolio.Achievements = append(portfolio.Achievements, item)
+	}
+
+	return portfolio, nil
+}
+
+// VerificationURL membuat link verifikasi bertanda tangan. Signature-nya permanen (tanpa exp)
+// agar bisa dicantumkan di CV; yang membatasi adalah status saat link dibuka: prestasi harus
+// masih verified dan dipublikasikan, lihat VerifyAchievementLink.
+func (s *PortfolioService) VerificationURL(baseURL string, achievementID string) string {
+	sig := s.Signer.Sign(verificationLinkPurpose, achievementID, time.Time{})
+	return baseURL + "/public/verify/" + url.PathEscape(achievementID) + "?sig=" + sig
+}
+
+// GetVerificationLink: GET /api/student/achievements/:id/verification-link
+func (s *PortfolioService) GetVerificationLink(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	id := c.Params("id")
+
+	achievement, err := s.Achievements.GetAchievementByID(id)
+	if err != nil {
+		return err
+	}
+	if achievement == nil {
+		return fiber.NewError(fiber.StatusNotFound, "Achievement not found")
+	}
+	if achievement.StudentID != claims.UserID {
+		return fiber.NewError(fiber.StatusForbidden, "You can only share your own achievement")
+	}
+
+	rec, err := s.Repo.FindVerification(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	if !rec.IsPublic {
+		return fiber.NewError(fiber.StatusConflict, "Publish the achievement before sharing its verification link")
+	}
+
+	return response.OK(c, "", fiber.Map{
+		"achievement_id":   id,
+		"verification_url": s.VerificationURL(c.BaseURL(), id),
+	})
+}
+
+// VerifyAchievementLink: GET /public/verify/:id?sig=
+// Signature salah dan prestasi yang tidak (lagi) verified atau sudah tidak dipublikasikan
+// sama-sama 404, sehingga link tidak bisa ditebak dari id prestasi saja. Mahasiswa
+// mencabut link yang sudah dibagikan dengan menonaktifkan is_public.
+func (s *PortfolioService) VerifyAchievementLink(c *fiber.Ctx) error {
+	id := c.Params("id")
+	invalid := fiber.NewError(fiber.StatusNotFound, "Verification link is invalid")
+
+	if !s.Signer.Verify(verificationLinkPurpose, id, time.Time{}, c.Query("sig")) {
+		return invalid
+	}
+
+	ctx := context.Background()
+
+	rec, err := s.Repo.FindVerification(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return invalid
+	}
+	if err != nil {
+		return err
+	}
+	if !rec.IsPublic {
+		return invalid
+	}
+
+	achievement, err := s.Achievements.GetAchievementByID(id)
+	if err != nil {
+		return err
+	}
+	if achievement == nil {
+		return invalid
+	}
+
+	return response.OK(c, "Achievement verified", model.AchievementVerification{
+		AchievementID:   id,
+		Title:           achievement.Title,
+		AchievementType: achievement.AchievementType,
+		StudentName:     rec.StudentName,
+		Status:          model.StatusVerified,
+		VerifiedAt:      rec.VerifiedAt,
+		VerifiedBy:      rec.VerifierName,
+	})
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/validation"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseBody membaca body JSON ke dst lalu memvalidasi tag `validate`-nya.
+// Body rusak → 400, pelanggaran aturan → validation.Errors (422 lewat ErrorHandler).
+func parseBody(c *fiber.Ctx, dst any) error {
+	if err := c.BodyParser(dst); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	return validation.Struct(dst)
+}
+
+// parseListQuery membaca ?limit=&page=&cursor=&sort=. sort diawali "-" berarti descending,
+// contoh sort=-created_at. Nama field divalidasi oleh repository.
+func parseListQuery(c *fiber.Ctx, defaultSort string) (model.ListQuery, error) {
+	q := model.ListQuery{
+		Limit:  c.QueryInt("limit", 20),
+		Page:   c.QueryInt("page", 1),
+		Cursor: c.Query("cursor"),
+		Sort:   c.Query("sort", defaultSort),
+	}
+
+	if q.Limit < 1 || q.Limit > 100 {
+		return q, fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+	if q.Page < 1 {
+		return q, fiber.NewError(fiber.StatusBadRequest, "page must be at least 1")
+	}
+
+	if strings.HasPrefix(q.Sort, "-") {
+		q.Sort, q.Desc = strings.TrimPrefix(q.Sort, "-"), true
+	}
+
+	return q, nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
+	"PROJECTUAS_BE/middleware"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetReviewQueue: GET /api/lecturer/queue?sort=submitted_at|-submitted_at&overdue=true&limit=&page=|cursor=
+// Prestasi submitted milik mahasiswa bimbingan, default yang paling lama menunggu lebih dulu.
+func (s *LecturesService) GetReviewQueue(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	query, err := parseListQuery(c, "submitted_at")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	filter := model.ReviewQueueFilter{LecturerUserID: claims.UserID}
+	if c.QueryBool("overdue") {
+		cutoff := now.Add(-s.ReviewSLA)
+		filter.OverdueBefore = &cutoff
+	}
+
+	items, page, err := s.Repo.GetReviewQueue(context.Background(), filter, query)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(items))
+	for i := range items {
+		ids[i] = items[i].MongoAchievementID
+	}
+
+	docs, err := s.Achievements.FindByIDs(context.Background(), ids)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch achievements")
+	}
+
+	byID := make(map[string]*model.Achievement, len(docs))
+	for i := range docs {
+		byID[docs[i].ID] = &docs[i]
+	}
+
+	for i := range items {
+		s.describeQueueItem(&items[i], now)
+		items[i].Achievement = byID[items[i].MongoAchievementID]
+	}
+
+	return response.Page(c, items, page)
+}
+
+// describeQueueItem mengisi indikator SLA dan masa berlaku claim
+func (s *LecturesService) describeQueueItem(item *model.ReviewQueueItem, now time.Time) {
+	item.WaitingHours = int(now.Sub(item.SubmittedAt).Hours())
+	item.SLADueAt = item.SubmittedAt.Add(s.ReviewSLA)
+	item.Overdue = now.After(item.SLADueAt)
+
+	if item.ClaimedAt != nil {
+		until := item.ClaimedAt.Add(s.ClaimLease)
+		if until.After(now) {
+			item.ClaimedUntil = &until
+		} else {
+			item.ClaimedBy, item.ClaimedAt = nil, nil // claim kedaluwarsa
+		}
+	}
+}
+
+// ClaimReview: POST /api/lecturer/queue/:id/claim
+// Menandai prestasi sedang direview selama ClaimLease; claim ulang memperpanjangnya.
+func (s *LecturesService) ClaimReview(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	achievementID := c.Params("id")
+
+	claimedAt, err := s.Repo.ClaimReview(context.Background(), achievementID, claims.UserID, time.Now().Add(-s.ClaimLease))
+	if err != nil {
+		return err
+	}
+
+	return response.OK(c, "Achievement claimed for review", fiber.Map{
+		"mongo_achievement_id": achievementID,
+		"claimed_by":           claims.UserID,
+		"claimed_at":           claimedAt,
+		"claimed_until":        claimedAt.Add(s.ClaimLease),
+	})
+}
+
+// ReleaseReview: DELETE /api/lecturer/queue/:id/claim
+func (s *LecturesService) ReleaseReview(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	if err := s.Repo.ReleaseReview(context.Background(), c.Params("id"), claims.UserID); err != nil {
+		return err
+	}
+
+	return response.OK(c, "Claim released", nil)
+}
+
+// DecideBatch: POST /api/lecturer/queue/decisions
+// Verify / reject banyak prestasi dalam satu transaksi. Item yang gagal (bukan bimbingan,
+// sudah tidak submitted, di-claim dosen lain) dilaporkan per item tanpa membatalkan item lain.
+// Dosen wali dan claim dicek lagi di UPDATE transisi, FindQueueItems hanya untuk pesan error yang jelas.
+func (s *LecturesService) DecideBatch(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req model.BulkDecisionRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	ids := make([]string, len(req.Items))
+	for i, item := range req.Items {
+		ids[i] = item.ID
+	}
+
+	queue, err := s.Repo.FindQueueItems(ctx, claims.UserID, ids)
+	if err != nil {
+		return err
+	}
+
+	docs, err := s.Achievements.FindByIDs(ctx, ids)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch achievements")
+	}
+	byID := make(map[string]*model.Achievement, len(docs))
+	for i := range docs {
+		byID[docs[i].ID] = &docs[i]
+	}
+
+	now := time.Now()
+	results := make([]model.BulkDecisionResult, len(req.Items))
+	seen := make(map[string]bool, len(req.Items))
+
+	var (
+		decisions []Decision
+		index     []int // decisions[j] berasal dari req.Items[index[j]]
+	)
+	for i, item := range req.Items {
+		results[i].ID = item.ID
+
+		decision, err := s.prepareDecision(ctx, item, queue, byID, seen, claims.UserID, now)
+		if err != nil {
+			failDecision(&results[i], err)
+			continue
+		}
+
+		decisions = append(decisions, decision)
+		index = append(index, i)
+	}
+
+	applied, err := s.Lifecycle.ApplyBatch(ctx, claims.UserID, decisions)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to save decisions")
+	}
+
+	succeeded := 0
+	for j, err := range applied {
+		result := &results[index[j]]
+		if err != nil {
+			failDecision(result, err)
+			continue
+		}
+
+		result.Success = true
+		result.Status = model.StatusVerified
+		if decisions[j].Action == ActionReject {
+			result.Status = model.StatusRejected
+		}
+		result.Points = decisions[j].Points
+		succeeded++
+	}
+
+	return response.OK(c, "Bulk decision processed", fiber.Map{
+		"total":     len(results),
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+		"results":   results,
+	})
+}
+
+// prepareDecision memeriksa satu item sebelum masuk transaksi dan menghitung poin untuk verify
+func (s *LecturesService) prepareDecision(ctx context.Context, item model.BulkDecisionItem, queue map[string]model.ReviewQueueItem, docs map[string]*model.Achievement, seen map[string]bool, lecturerUserID string, now time.Time) (Decision, error) {
+	if seen[item.ID] {
+		return Decision{}, fiber.NewError(fiber.StatusBadRequest, "Duplicate achievement in request")
+	}
+	seen[item.ID] = true
+
+	queued, ok := queue[item.ID]
+	if !ok {
+		return Decision{}, fiber.NewError(fiber.StatusNotFound, "Achievement is not in your review queue")
+	}
+
+	s.describeQueueItem(&queued, now)
+	if queued.ClaimedBy != nil && *queued.ClaimedBy != lecturerUserID {
+		return Decision{}, fiber.NewError(fiber.StatusConflict, "Achievement is claimed by another lecturer")
+	}
+
+	decision := s.reviewDecision(item.ID, ActionVerify)
+	if item.Status == model.StatusRejected {
+		decision.Action = ActionReject
+		decision.Note = item.RejectionReason
+		return decision, nil
+	}
+
+	doc, ok := docs[item.ID]
+	if !ok {
+		return Decision{}, fiber.NewError(fiber.StatusNotFound, "Achievement not found")
+	}
+
+	points, err := s.Scoring.PointsFor(ctx, doc)
+	if err != nil {
+		return Decision{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to calculate points")
+	}
+	decision.Points = &points
+
+	return decision, nil
+}
+
+// failDecision mengisi kode dan pesan error untuk satu item; error tak dikenal tidak dikirim ke client
+func failDecision(result *model.BulkDecisionResult, err error) {
+	var (
+		fiberErr  *fiber.Error
+		domainErr *repository.DomainError
+	)
+
+	switch {
+	case errors.As(err, &fiberErr):
+		result.Code, result.Error = response.CodeForStatus(fiberErr.Code), fiberErr.Message
+	case errors.As(err, &domainErr) && errors.Is(err, repository.ErrConflict):
+		result.Code, result.Error = response.CodeConflict, domainErr.Message
+	case errors.As(err, &domainErr) && errors.Is(err, repository.ErrNotFound):
+		result.Code, result.Error = response.CodeNotFound, domainErr.Message
+	case errors.As(err, &domainErr) && errors.Is(err, repository.ErrForbidden):
+		result.Code, result.Error = response.CodeForbidden, domainErr.Message
+	default:
+		log.Printf("bulk decision %s: %v\n", result.ID, err)
+		result.Code, result.Error = response.CodeInternal, "Failed to save decision"
+	}
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	model "PROJECTUAS_BE/app/Model"
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ScoringService mengelola rubrik poin dan menghitung poin prestasi di server.
+// Setiap perubahan rubrik memicu recalculation poin semua prestasi verified.
+type ScoringService struct {
+	Repo         repository.ScoringRuleRepository
+	Achievements repository.AchievementRepository
+	Refs         repository.AchievementReferenceRepository
+
+	recalcMu sync.Mutex // satu recalculation dalam satu waktu
+}
+
+func NewScoringService(repo repository.ScoringRuleRepository, achievements repository.AchievementRepository, refs repository.AchievementReferenceRepository) *ScoringService {
+	return &ScoringService{
+		Repo:         repo,
+		Achievements: achievements,
+		Refs:         refs,
+	}
+}
+
+func (s *ScoringService) GetRules(c *fiber.Ctx) error {
+	rules, err := s.Repo.GetAll(context.Background())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch scoring rules")
+	}
+
+	return response.OK(c, "", rules)
+}
+
+func (s *ScoringService) CreateRule(c *fiber.Ctx) error {
+	req, err := parseScoringRule(c)
+	if err != nil {
+		return err
+	}
+
+	// aturan dengan kriteria sama → 409 lewat ErrorHandler
+	rule, err := s.Repo.Create(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	s.scheduleRecalculation()
+
+	return response.Created(c, "Scoring rule created successfully", rule)
+}
+
+func (s *ScoringService) UpdateRule(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid scoring rule id")
+	}
+
+	req, err := parseScoringRule(c)
+	if err != nil {
+		return err
+	}
+
+	rule, err := s.Repo.Update(context.Background(), id, req)
+	if err != nil {
+		return err
+	}
+
+	s.scheduleRecalculation()
+
+	return response.OK(c, "Scoring rule updated successfully", rule)
+}
+
+func (s *ScoringService) DeleteRule(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid scoring rule id")
+	}
+
+	err := s.Repo.Delete(context.Background(), id)
+	if err != nil {
+		return err
+	}
+
+	s.scheduleRecalculation()
+
+	return response.OK(c, "Scoring rule deleted successfully", nil)
+}
+
+// RecalculatePoints: POST /api/admin/scoring-rules/recalculate, dijalankan langsung
+func (s *ScoringService) RecalculatePoints(c *fiber.Ctx) error {
+	result, err := s.Recalculate(context.Background())
+	if err != nil {
+		log.Println("ERROR RECALCULATE POINTS:", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to recalculate points")
+	}
+
+	return response.OK(c, "", result)
+}
+
+// PointsFor menghitung poin satu prestasi dengan rubrik saat ini
+func (s *ScoringService) PointsFor(ctx context.Context, achievement *model.Achievement) (int, error) {
+	rules, err := s.Repo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return ScoreAchievement(rules, achievement), nil
+}
+
+// Recalculate menghitung ulang poin semua prestasi verified dan
+// menyimpan yang berubah (MongoDB diperbarui lewat outbox)
+func (s *ScoringService) Recalculate(ctx context.Context) (*model.RecalculationResult, error) {
+	s.recalcMu.Lock()
+	defer s.recalcMu.Unlock()
+
+	rules, err := s.Repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := s.Refs.ListLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, ref := range refs {
+		if ref.Status == model.StatusVerified {
+			ids = append(ids, ref.MongoAchievementID)
+		}
+	}
+
+	achievements, err := s.Achievements.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*model.Achievement, len(achievements))
+	for i := range achievements {
+		byID[achievements[i].ID] = &achievements[i]
+	}
+
+	result := &model.RecalculationResult{Checked: len(ids)}
+
+	for _, id := range ids {
+		achievement, ok := byID[id]
+		if !ok {
+			result.Missing++
+			continue
+		}
+
+		changed, err := s.Refs.UpdatePoints(ctx, id, ScoreAchievement(rules, achievement))
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			result.Updated++
+		}
+	}
+
+	return result, nil
+}
+
+// scheduleRecalculation menjalankan Recalculate di background setelah rubrik berubah
+func (s *ScoringService) scheduleRecalculation() {
+	go func() {
+		result, err := s.Recalculate(context.Background())
+		if err != nil {
+			log.Println("points recalculation error:", err)
+			return
+		}
+		log.Printf("points recalculation: %d checked, %d updated\n", result.Checked, result.Updated)
+	}()
+}
+
+// ScoreAchievement memilih aturan yang paling spesifik untuk prestasi ini.
+// Jika dua aturan sama spesifiknya, poin tertinggi yang dipakai. Tidak ada aturan → 0.
+func ScoreAchievement(rules []model.ScoringRule, a *model.Achievement) int {
+	best, bestSpecificity := 0, -1
+	competition := a.Details.Competition() // kosong untuk tipe selain lomba
+
+	for _, rule := range rules {
+		if !strings.EqualFold(rule.AchievementType, a.AchievementType) {
+			continue
+		}
+
+		specificity := 0
+		if rule.CompetitionLevel != nil {
+			if !strings.EqualFold(*rule.CompetitionLevel, competition.CompetitionLevel) {
+				continue
+			}
+			specificity++
+		}
+		if rule.Rank != nil {
+			if *rule.Rank != competition.Rank {
+				continue
+			}
+			specificity++
+		}
+		if rule.MedalType != nil {
+			if !strings.EqualFold(*rule.MedalType, competition.MedalType) {
+				continue
+			}
+			specificity++
+		}
+
+		if specificity > bestSpecificity || (specificity == bestSpecificity && rule.Points > best) {
+			best, bestSpecificity = rule.Points, specificity
+		}
+	}
+
+	return best
+}
+
+func parseScoringRule(c *fiber.Ctx) (*model.ScoringRuleRequest, error) {
+	var req model.ScoringRuleRequest
+	if err := parseBody(c, &req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
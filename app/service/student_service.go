@@ -3,23 +3,24 @@ package service
 import (
 	model "PROJECTUAS_BE/app/Model"
 	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
 	"PROJECTUAS_BE/middleware"
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"log"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 type Studentservice struct {
-	repo repository.StudentRepository
+	repo         repository.StudentRepository
+	achievements repository.AchievementRepository
+	lifecycle    *AchievementLifecycle
 }
 
-func NewAStudentService(repo repository.StudentRepository) *Studentservice {
-	return &Studentservice{repo: repo}
+func NewAStudentService(repo repository.StudentRepository, achievements repository.AchievementRepository, lifecycle *AchievementLifecycle) *Studentservice {
+	return &Studentservice{repo: repo, achievements: achievements, lifecycle: lifecycle}
 }
 
 func (s *Studentservice) GetStudent(c *fiber.Ctx) error {
@@ -29,49 +30,55 @@ func (s *Studentservice) GetStudent(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid user ID")
 	}
 
-	// Hanya admin yang bisa mengakses data student
-	claims := c.Locals("claims")
-	if claims == nil {
+	// Route group membatasi ke admin / dosen; dosen hanya melihat mahasiswa bimbingannya
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
 		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	userClaims := claims.(*middleware.Claims)
-	if userClaims.Role != "mahasiswa" {
-		return fiber.NewError(fiber.StatusForbidden, "Access denied: mahasiswa only")
+	if err := s.lifecycle.canView(context.Background(), claims, id); err != nil {
+		return err
 	}
 
 	// Ambil data student berdasarkan user ID
 	student, err := s.repo.GetStudentByUserID(id)
 	if err != nil {
-		return fiber.NewError(fiber.StatusNotFound, "Student not found")
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"status": "success",
-		"data":   student,
-	})
+	return response.OK(c, "", student)
 }
 
 func (s *Studentservice) GetAllStudents(c *fiber.Ctx) error {
-	claims := c.Locals("claims")
-	if claims == nil {
+	claims, ok := c.Locals("claims").(*middleware.Claims)
+	if !ok {
 		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	userClaims := claims.(*middleware.Claims)
-	if userClaims.Role != "mahasiswa" {
-		return fiber.NewError(fiber.StatusForbidden, "Access denied: mahasiswa only")
+	// dosen hanya melihat mahasiswa bimbingannya, sama seperti daftar prestasi
+	owners, err := s.lifecycle.visibleOwners(context.Background(), claims)
+	if err != nil {
+		return err
 	}
 
-	students, err := s.repo.GetAllStudents()
+	// ?program_study=&academic_year=&sort=full_name&limit=&page=|cursor=
+	query, err := parseListQuery(c, "full_name")
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"status":   "success",
-		"students": students,
-	})
+	filter := model.StudentFilter{
+		ProgramStudy: c.Query("program_study"),
+		AcademicYear: c.Query("academic_year"),
+		Owners:       owners,
+	}
+
+	students, page, err := s.repo.GetAllStudents(context.Background(), filter, query)
+	if err != nil {
+		return err
+	}
+
+	return response.Page(c, students, page)
 }
 
 func (s *Studentservice) SubmitAchievement(c *fiber.Ctx) error {
@@ -81,9 +88,6 @@ func (s *Studentservice) SubmitAchievement(c *fiber.Ctx) error {
 	}
 
 	userClaims := claims.(*middleware.Claims)
-	if userClaims.Role != "mahasiswa" {
-		return fiber.NewError(fiber.StatusForbidden, "Access denied: mahasiswa only")
-	}
 
 	fmt.Println("Id user:", userClaims.UserID)
 
@@ -92,45 +96,38 @@ func (s *Studentservice) SubmitAchievement(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Achievement ID is required")
 	}
 
-	studentid, err := s.repo.GetStudentIDByUserID(
-		context.Background(),
-		userClaims.UserID,
-	)
+	// Dokumen harus ada di MongoDB dan milik mahasiswa yang login
+	achievement, err := s.achievements.GetAchievementByID(achievementID)
 	if err != nil {
-		return fiber.NewError(
-			fiber.StatusNotFound,
-			"Student not found",
-		)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch achievement")
 	}
 
-	// ===== 3. Prepare Data =====
-	now := time.Now()
+	if achievement == nil {
+		return fiber.NewError(fiber.StatusNotFound, "Achievement not found")
+	}
 
-	ref := &model.AchievementReference{
-		ID:                 uuid.New().String(),
-		StudentID:          studentid,
-		MongoAchievementID: achievementID,
-		Status:             "submitted",
-		SubmittedAt:        &now,
+	if achievement.StudentID != userClaims.UserID {
+		return fiber.NewError(fiber.StatusForbidden, "You can only submit your own achievement")
 	}
 
-	err = s.repo.Submit(context.Background(), ref)
+	// draft → submitted, atau rejected → submitted (resubmit)
+	ref, err := s.lifecycle.Apply(
+		context.Background(),
+		achievementID,
+		ActionSubmit,
+		userClaims.UserID,
+		nil,
+	)
 	if err != nil {
 		log.Println("ERROR SUBMIT ACHIEVEMENT:", err)
-		return fiber.NewError(
-			fiber.StatusInternalServerError,
-			err.Error(),
-		)
-	}
-
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "Achievement submitted successfully",
-		"data": fiber.Map{
-			"id":                   ref.ID,
-			"mongo_achievement_id": ref.MongoAchievementID,
-			"status":               ref.Status,
-			"submitted_at":         ref.SubmittedAt,
-		},
+		return err
+	}
+
+	return response.Created(c, "Achievement submitted successfully", fiber.Map{
+		"id":                   ref.ID,
+		"mongo_achievement_id": ref.MongoAchievementID,
+		"status":               ref.Status,
+		"submitted_at":         ref.SubmittedAt,
 	})
 }
 
@@ -140,13 +137,6 @@ func (s *Studentservice) UpdateAdvisor(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	userClaims := claims.(*middleware.Claims)
-
-	// ===== 2. Role Check =====
-	if userClaims.Role != "admin" {
-		return fiber.NewError(fiber.StatusForbidden, "Access denied")
-	}
-
 	studentID := c.Params("id")
 	if studentID == "" {
 		return fiber.NewError(fiber.StatusBadRequest, "Student ID is required")
@@ -154,8 +144,8 @@ func (s *Studentservice) UpdateAdvisor(c *fiber.Ctx) error {
 
 	// ===== 4. Body =====
 	var req model.StudentAdvisorRequest
-	if err := c.BodyParser(&req); err != nil || req.AdvisorId == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "Advisor ID is required")
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// ===== 5. Update =====
@@ -166,18 +156,15 @@ func (s *Studentservice) UpdateAdvisor(c *fiber.Ctx) error {
 	)
 
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return fiber.NewError(fiber.StatusNotFound, "Student not found")
+		if errors.Is(err, repository.ErrNotFound) {
+			return err
 		}
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update advisor")
 	}
 
 	// ===== 6. Response =====
-	return c.JSON(fiber.Map{
-		"message": "Advisor updated successfully",
-		"data": fiber.Map{
-			"student_id": studentID,
-			"advisor_id": req.AdvisorId,
-		},
+	return response.OK(c, "Advisor updated successfully", fiber.Map{
+		"student_id": studentID,
+		"advisor_id": req.AdvisorId,
 	})
 }
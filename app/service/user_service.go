@@ -1,9 +1,12 @@
 package service
 
 import (
+	model "PROJECTUAS_BE/app/Model"
 	"PROJECTUAS_BE/app/repository"
-	"PROJECTUAS_BE/middleware"
+	"PROJECTUAS_BE/app/response"
+	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -12,7 +15,6 @@ import (
 
 type UserService struct {
 	Repo repository.UserRepository
-	repo repository.StudentPostgres
 }
 
 func NewUserService(repo repository.UserRepository) *UserService {
@@ -33,23 +35,27 @@ func (s *UserService) GetAllUsers(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	userClaims := claims.(*middleware.Claims)
+	// ?role=dosen&is_active=true&sort=-created_at&limit=&page=|cursor=
+	query, err := parseListQuery(c, "username")
+	if err != nil {
+		return err
+	}
 
-	// Hanya admin yang bisa
-	if userClaims.Role != "admin" {
-		return fiber.NewError(fiber.StatusForbidden, "Access denied: Admin only")
+	filter := model.UserFilter{Role: c.Query("role")}
+	if v := c.Query("is_active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "is_active must be true or false")
+		}
+		filter.IsActive = &active
 	}
 
-	// Ambil semua user
-	users, err := s.Repo.GetAllUsers()
+	users, page, err := s.Repo.GetAllUsers(context.Background(), filter, query)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"status": "success",
-		"data":   users,
-	})
+	return response.Page(c, users, page)
 }
 
 func (s *UserService) GetUsersByID(c *fiber.Ctx) error {
@@ -71,22 +77,12 @@ func (s *UserService) GetUsersByID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	userClaims := claims.(*middleware.Claims)
-
-	// Hanya admin yang bisa
-	if userClaims.Role != "admin" {
-		return fiber.NewError(fiber.StatusForbidden, "Access denied: Admin only")
-	}
-
 	user, err := s.Repo.GetUserByID(id)
 	if err != nil {
-		return fiber.NewError(fiber.StatusNotFound, "user not found")
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"status": "success",
-		"data":   user,
-	})
+	return response.OK(c, "", user)
 }
 
 func (s *UserService) CreateUser(c *fiber.Ctx) error {
@@ -95,28 +91,11 @@ func (s *UserService) CreateUser(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	userClaims := claims.(*middleware.Claims)
-
-	// Hanya admin yang bisa
-	if userClaims.Role != "admin" {
-		return fiber.NewError(fiber.StatusForbidden, "Access denied: Admin only")
-	}
-
-	var body struct {
-		Username string `json:"username"`
-		Email    string `json:"email"`
-		Password string `json:"password_hash"`
-		RoleID   string `json:"role_id"`
-		Fullname string `json:"full_name"`
-	}
-
-	if err := c.BodyParser(&body); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid input")
-	}
+	var body model.CreateUserRequest
 
 	// =============== Validasi ===============
-	if body.Username == "" || body.Email == "" || body.Password == "" || body.RoleID == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "All fields are required")
+	if err := parseBody(c, &body); err != nil {
+		return err
 	}
 
 	hashed, err := bcrypt.GenerateFromPassword([]byte(body.Password), bcrypt.DefaultCost)
@@ -125,35 +104,14 @@ func (s *UserService) CreateUser(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to hash password")
 	}
 
-	userID, err := s.Repo.CreateUser(body.Username, body.Email, string(hashed), body.RoleID, body.Fullname)
+	// user + profil student / lecturer dibuat dalam satu transaksi
+	// role / profil / advisor tidak valid → 400, username atau email terpakai → 409
+	profile, err := s.Repo.CreateUserWithProfile(context.Background(), &body, string(hashed))
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create user")
+		return err
 	}
 
-	// 2️⃣ Get role name
-	roleName, err := s.Repo.GetRoleNameByRoleID(body.RoleID)
-	// roleName, err := s.Repo.GetRoleNameByRoleID(body.RoleID)
-	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid role ID")
-	}
-
-	// 3️⃣ If student → insert into students table
-	if roleName == "student" || roleName == "mahasiswa" {
-		err := s.repo.CreateStudent(userID)
-		if err != nil {
-			return fiber.NewError(fiber.StatusInternalServerError, "User created but failed to create student")
-		}
-	}
-
-	return c.JSON(fiber.Map{
-		"message": "User created successfully",
-		"data": fiber.Map{
-			"username": body.Username,
-			"email":    body.Email,
-			"role_id":  body.RoleID,
-			"fullname": body.Fullname,
-		},
-	})
+	return response.Created(c, "User created successfully", profile)
 
 }
 
@@ -175,33 +133,19 @@ func (s *UserService) UpdateUserByID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	userClaims := claims.(*middleware.Claims)
-
-	// Periksa apakah admin
-	if userClaims.Role != "admin" {
-		return fiber.NewError(fiber.StatusForbidden, "Access denied: Admin only")
-	}
-
 	// Bind request body
-	var body struct {
-		Name  string `json:"name"`
-		Email string `json:"email"`
-	}
-
-	if err := c.BodyParser(&body); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	var body model.UpdateUserRequest
+	if err := parseBody(c, &body); err != nil {
+		return err
 	}
 
 	// Update melalui repository
 	err := s.Repo.UpdateUserByID(id, body.Name, body.Email)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"status":  "success",
-		"message": "user updated successfully",
-	})
+	return response.OK(c, "User updated successfully", nil)
 }
 
 func (s *UserService) DeleteUserByID(c *fiber.Ctx) error {
@@ -222,22 +166,11 @@ func (s *UserService) DeleteUserByID(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	userClaims := claims.(*middleware.Claims)
-
-	// Hanya admin yang boleh
-	if userClaims.Role != "admin" {
-		return fiber.NewError(fiber.StatusForbidden, "Access denied: Admin only")
-	}
-
 	// Delete user melalui repository
 	err := s.Repo.DeleteUserByID(id)
 	if err != nil {
-		return fiber.NewError(fiber.StatusNotFound, err.Error())
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"status":  "success",
-		"message": "user deleted successfully",
-	})
-
+	return response.OK(c, "User deleted successfully", nil)
 }
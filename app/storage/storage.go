@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore menyimpan file lampiran. Key memakai pemisah "/", contoh
+// "achievements/<uuid>.pdf", dan tidak boleh keluar dari root storage.
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]BlobInfo, error)
+}
+
+// BlobInfo: satu file hasil List
+type BlobInfo struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Object adalah file yang sedang dibaca. Body harus di-Close oleh pemanggil;
+// Seek dipakai untuk melayani HTTP Range.
+type Object struct {
+	Body        io.ReadSeekCloser
+	Size        int64
+	ContentType string // kosong jika backend tidak menyimpan content type
+	ModTime     time.Time
+}
+
+// cleanKey menolak key absolut dan key yang mengandung "..", hasilnya tanpa "/" di depan
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || strings.HasPrefix(cleaned, "../") || cleaned == ".." {
+		return "", ErrInvalidKey
+	}
+
+	return cleaned, nil
+}
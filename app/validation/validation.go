@@ -0,0 +1,156 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError adalah satu pelanggaran aturan validasi pada sebuah field request.
+// Field memakai nama JSON dengan path bertitik, contoh "student.academic_year".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Errors berisi semua field yang gagal validasi, dikirim sebagai response 422
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, f := range e {
+		parts = append(parts, f.Field+" "+f.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Prefix menambahkan path induk, dipakai untuk struct yang divalidasi terpisah (misal details)
+func (e Errors) Prefix(parent string) Errors {
+	out := make(Errors, len(e))
+	for i, f := range e {
+		f.Field = parent + "." + f.Field
+		out[i] = f
+	}
+	return out
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	// nama field diambil dari tag json agar sama dengan body request
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+
+	// notfuture: tanggal kejadian tidak boleh setelah hari ini
+	v.RegisterValidation("notfuture", func(fl validator.FieldLevel) bool {
+		t, ok := fl.Field().Interface().(time.Time)
+		if !ok {
+			return false
+		}
+		return !t.After(time.Now())
+	})
+
+	return v
+}
+
+// Struct memvalidasi tag `validate` pada s. Hasilnya nil atau Errors.
+func Struct(s any) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	fieldErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	out := make(Errors, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		out = append(out, FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: message(fe),
+		})
+	}
+	return out
+}
+
+// fieldPath membuang nama struct paling luar dari namespace ("CreateUserRequest.email" → "email")
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+func message(fe validator.FieldError) string {
+	isString := fe.Kind() == reflect.String
+	isCollection := fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map
+
+	switch fe.Tag() {
+	case "required", "required_if", "required_with":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "notfuture":
+		return "must not be in the future"
+	case "numeric":
+		return "must contain only digits"
+	case "url":
+		return "must be a valid URL"
+	case "len":
+		if isString {
+			return fmt.Sprintf("must be exactly %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must have exactly %s items", fe.Param())
+	case "min", "gte":
+		switch {
+		case isString:
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		case isCollection:
+			return fmt.Sprintf("must have at least %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max", "lte":
+		switch {
+		case isString:
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		case isCollection:
+			return fmt.Sprintf("must have at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "gtfield", "gtefield":
+		return "must be after " + lowerFirst(fe.Param())
+	}
+	return "failed on the '" + fe.Tag() + "' rule"
+}
+
+// param gtfield berisi nama field Go, dibuat sama dengan nama JSON (camelCase)
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
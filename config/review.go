@@ -0,0 +1,22 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ReviewSLA membaca batas waktu review dosen dari env REVIEW_SLA_HOURS (default 72)
+func ReviewSLA() time.Duration {
+	v := os.Getenv("REVIEW_SLA_HOURS")
+	if v == "" {
+		return 72 * time.Hour
+	}
+
+	hours, err := strconv.Atoi(v)
+	if err != nil || hours < 1 {
+		log.Fatalf("Invalid REVIEW_SLA_HOURS %q", v)
+	}
+	return time.Duration(hours) * time.Hour
+}
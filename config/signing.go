@@ -0,0 +1,22 @@
+package config
+
+import (
+	"PROJECTUAS_BE/app/signing"
+	"log"
+	"os"
+)
+
+// LinkSigner membuat signer untuk link publik (verifikasi prestasi, download lampiran).
+// LINK_SIGNING_SECRET wajib diisi dan harus berbeda dari JWT_SECRET, supaya bocornya
+// salah satu key tidak ikut membuka yang lain.
+func LinkSigner() *signing.Signer {
+	secret := os.Getenv("LINK_SIGNING_SECRET")
+	if secret == "" {
+		log.Fatal("LINK_SIGNING_SECRET is not set in .env")
+	}
+	if secret == os.Getenv("JWT_SECRET") {
+		log.Fatal("LINK_SIGNING_SECRET must be different from JWT_SECRET")
+	}
+
+	return signing.New(secret)
+}
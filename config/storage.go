@@ -0,0 +1,68 @@
+package config
+
+import (
+	"PROJECTUAS_BE/app/service"
+	"PROJECTUAS_BE/app/storage"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+// NewBlobStore memilih backend lampiran dari env STORAGE_DRIVER:
+//   - local (default): file di STORAGE_LOCAL_ROOT, default ./uploads
+//   - s3: S3_ENDPOINT, S3_ACCESS_KEY, S3_SECRET_KEY, S3_BUCKET, S3_REGION, S3_USE_SSL=true|false
+func NewBlobStore() storage.BlobStore {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		root := os.Getenv("STORAGE_LOCAL_ROOT")
+		if root == "" {
+			root = "./uploads"
+		}
+		fmt.Println("Attachment storage: local", root)
+		return storage.NewLocalStore(root)
+
+	case "s3":
+		store, err := storage.NewS3Store(storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			UseSSL:    os.Getenv("S3_USE_SSL") != "false",
+		})
+		if err != nil {
+			log.Fatal("Failed to configure S3 storage: ", err)
+		}
+		fmt.Println("Attachment storage: s3", os.Getenv("S3_ENDPOINT"))
+		return store
+
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q, use local or s3", driver)
+		return nil
+	}
+}
+
+// AttachmentPolicy membaca batas lampiran dari env ATTACHMENT_MAX_SIZE_MB (default 5)
+// dan ATTACHMENT_MAX_COUNT (default 10)
+func AttachmentPolicy() service.AttachmentPolicy {
+	policy := service.DefaultAttachmentPolicy()
+
+	if v := os.Getenv("ATTACHMENT_MAX_SIZE_MB"); v != "" {
+		mb, err := strconv.Atoi(v)
+		if err != nil || mb < 1 {
+			log.Fatalf("Invalid ATTACHMENT_MAX_SIZE_MB %q", v)
+		}
+		policy.MaxSize = int64(mb) << 20
+	}
+
+	if v := os.Getenv("ATTACHMENT_MAX_COUNT"); v != "" {
+		count, err := strconv.Atoi(v)
+		if err != nil || count < 1 {
+			log.Fatalf("Invalid ATTACHMENT_MAX_COUNT %q", v)
+		}
+		policy.MaxCount = count
+	}
+
+	return policy
+}
@@ -5,7 +5,7 @@ import (
 	"PROJECTUAS_BE/app/service"
 	"PROJECTUAS_BE/config"
 
-	// "PROJECTUAS_BE/middleware"
+	"PROJECTUAS_BE/middleware"
 	"PROJECTUAS_BE/routes"
 	"context"
 	"fmt"
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -64,24 +65,70 @@ func main() {
 	// 🟨 Init Auth + Generate Sample Token
 	// ===============================
 	db := client.Database(dbName)
-	app := fiber.New()
+	attachmentPolicy := config.AttachmentPolicy()
+	app := fiber.New(fiber.Config{
+		ErrorHandler: middleware.ErrorHandler,
+		// upload lampiran + overhead multipart, minimal default Fiber (4 MB)
+		BodyLimit: max(int(attachmentPolicy.MaxSize)+1<<20, fiber.DefaultBodyLimit),
+	})
+	app.Use(requestid.New()) // header X-Request-ID, ikut di setiap response
+
+	// link publik (verifikasi prestasi, download lampiran) ditandatangani HMAC dengan key sendiri
+	linkSigner := config.LinkSigner()
+	blobStore := config.NewBlobStore()
+
 	userRepo := repository.NewUserRepository(pgDB)
 	UserService := service.NewUserService(userRepo)
 	AuthRepo := repository.NewAuthRepository(pgDB)
-	AuthService := service.NewAuthService(AuthRepo)
+	RefreshRepo := repository.NewRefreshTokenRepository(pgDB)
+	AuthService := service.NewAuthService(AuthRepo, RefreshRepo)
 	studentRepo := repository.NewStudentRepository(pgDB)
-	Studentservice := service.NewAStudentService(studentRepo)
+	RefRepo := repository.NewAchievementReferenceRepository(pgDB)
+	Lifecycle := service.NewAchievementLifecycle(RefRepo, studentRepo)
 	AchieveRepo := repository.NewAchievementMongo(db)
-	AchieveService := service.NewAchievementService(AchieveRepo)
+	if err := AchieveRepo.EnsureIndexes(ctx); err != nil {
+		log.Println("⚠️ Failed to create achievement indexes, search unavailable:", err)
+	}
+	Studentservice := service.NewAStudentService(studentRepo, AchieveRepo, Lifecycle)
+	AchieveService := service.NewAchievementService(AchieveRepo, Lifecycle, blobStore, linkSigner, attachmentPolicy)
+	ConsistencyService := service.NewConsistencyService(AchieveRepo, Lifecycle)
+	ScoringService := service.NewScoringService(repository.NewScoringRuleRepository(pgDB), AchieveRepo, RefRepo)
 	LectureRepo := repository.NewLecturesRepository(pgDB)
-	Lectureservice := service.NewLecturesService(LectureRepo)
+	Lectureservice := service.NewLecturesService(LectureRepo, Lifecycle, AchieveRepo, ScoringService)
+	Lectureservice.ReviewSLA = config.ReviewSLA()
 	ReportRepo := repository.NewReportRepository(pgDB)
-	ReportService := service.NewReportService(ReportRepo)
+	ReportService := service.NewReportService(ReportRepo, AchieveRepo)
+	PermissionRepo := repository.NewPermissionRepository(pgDB)
+	PermissionService := service.NewPermissionService(PermissionRepo)
+	PortfolioService := service.NewPortfolioService(repository.NewPortfolioRepository(pgDB), AchieveRepo, linkSigner)
+
+	// ===============================
+	// 🟨 Token Revocation Store (PostgreSQL)
+	// ===============================
+	middleware.SetRevocationStore(repository.NewRevocationRepository(pgDB))
+
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	middleware.StartRevocationSweeper(sweeperCtx, 15*time.Minute)
+
+	// ===============================
+	// 🟨 Outbox Worker (PostgreSQL → MongoDB)
+	// ===============================
+	outboxCtx, stopOutbox := context.WithCancel(context.Background())
+	defer stopOutbox()
+	service.NewOutboxWorker(repository.NewOutboxRepository(pgDB), AchieveRepo).Start(outboxCtx, 5*time.Second)
+
+	// ===============================
+	// 🟨 Attachment GC (file tanpa referensi)
+	// ===============================
+	gcCtx, stopGC := context.WithCancel(context.Background())
+	defer stopGC()
+	service.NewAttachmentGC(AchieveRepo, blobStore).Start(gcCtx, 6*time.Hour)
 
 	// ===============================
 	// 🟨 Setup Routes
 	// ===============================
-	routes.SetupRoutes(app, UserService, Studentservice, AchieveService, Lectureservice, ReportService, AuthService)
+	routes.SetupRoutes(app, UserService, Studentservice, AchieveService, Lectureservice, ReportService, AuthService, PermissionService, ConsistencyService, ScoringService, PortfolioService)
 
 	// ===============================
 	// 🟨 Run Server
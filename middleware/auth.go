@@ -2,15 +2,22 @@ package middleware
 
 import (
 	// "errors"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var defaultExpMinutes int64 = 60 * 24 // 24 Jam
+var defaultExpMinutes int64 = 15 // access token dibuat singkat, diperpanjang lewat refresh token
+
+var defaultRefreshExpDays int64 = 30
 
 type Claims struct {
 	UserID      string   `json:"user_id"`
@@ -18,13 +25,14 @@ type Claims struct {
 	Email       string   `json:"email"`
 	Role        string   `json:"role"`
 	Permissions []string `json:"permissions"`
+	SessionID   string   `json:"sid,omitempty"` // family refresh token yang menerbitkan access token ini
 	jwt.RegisteredClaims
 }
 
 // =====================================================
 // ============ GENERATE TOKEN ==========================
 // =====================================================
-func GenerateToken(userID, name, email, role string, permissions []string) (string, error) {
+func GenerateToken(userID, name, email, role string, permissions []string, sessionID string) (string, error) {
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
 		return "", fmt.Errorf("JWT_SECRET is not set in .env")
@@ -39,7 +47,9 @@ func GenerateToken(userID, name, email, role string, permissions []string) (stri
 		Email:       email,
 		Role:        role,
 		Permissions: permissions,
+		SessionID:   sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(), // jti, dipakai sebagai key revocation store
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(exp),
 			Issuer:    "project_uas",
@@ -77,6 +87,32 @@ func ParseToken(tokenStr string) (*Claims, error) {
 
 
 
+// =====================================================
+// ============ REFRESH TOKEN HELPERS ==================
+// =====================================================
+func AccessTokenTTL() time.Duration {
+	return time.Minute * time.Duration(defaultExpMinutes)
+}
+
+func RefreshTokenTTL() time.Duration {
+	return time.Hour * 24 * time.Duration(defaultRefreshExpDays)
+}
+
+// GenerateRefreshToken membuat token acak (opaque), bukan JWT
+func GenerateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashRefreshToken dipakai agar database hanya menyimpan hash, bukan token asli
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // =====================================================
 // ============ PASSWORD HELPERS ========================
 // =====================================================
@@ -1,37 +1,119 @@
 package middleware
 
 import (
+	"context"
+	"log"
 	"sync"
 	"time"
 )
 
-var tokenBlacklist = struct {
+// RevocationStore menyimpan JTI token yang sudah di-logout sampai token tersebut expired
+type RevocationStore interface {
+	Revoke(ctx context.Context, jti string, exp time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+// memoryRevocationStore hanya cocok untuk satu instance (data hilang saat restart)
+type memoryRevocationStore struct {
 	sync.RWMutex
 	data map[string]time.Time
-}{data: make(map[string]time.Time)}
+}
 
-func BlacklistToken(token string, exp time.Time) {
-	tokenBlacklist.Lock()
-	tokenBlacklist.data[token] = exp
-	tokenBlacklist.Unlock()
+func NewMemoryRevocationStore() RevocationStore {
+	return &memoryRevocationStore{data: make(map[string]time.Time)}
 }
 
-func IsTokenBlacklisted(token string) bool {
-	tokenBlacklist.RLock()
-	exp, exists := tokenBlacklist.data[token]
-	tokenBlacklist.RUnlock()
+func (s *memoryRevocationStore) Revoke(ctx context.Context, jti string, exp time.Time) error {
+	s.Lock()
+	s.data[jti] = exp
+	s.Unlock()
+	return nil
+}
+
+func (s *memoryRevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	s.RLock()
+	exp, exists := s.data[jti]
+	s.RUnlock()
 
 	if !exists {
-		return false
+		return false, nil
 	}
 
 	// Auto remove jika token sudah expired
 	if time.Now().After(exp) {
-		tokenBlacklist.Lock()
-		delete(tokenBlacklist.data, token)
-		tokenBlacklist.Unlock()
-		return false
+		s.Lock()
+		delete(s.data, jti)
+		s.Unlock()
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (s *memoryRevocationStore) PurgeExpired(ctx context.Context) (int64, error) {
+	now := time.Now()
+	var purged int64
+
+	s.Lock()
+	for jti, exp := range s.data {
+		if now.After(exp) {
+			delete(s.data, jti)
+			purged++
+		}
 	}
+	s.Unlock()
+
+	return purged, nil
+}
+
+var (
+	revocationMu    sync.RWMutex
+	revocationStore = NewMemoryRevocationStore()
+)
+
+// SetRevocationStore mengganti store default (in-memory), misalnya dengan store PostgreSQL
+func SetRevocationStore(store RevocationStore) {
+	revocationMu.Lock()
+	revocationStore = store
+	revocationMu.Unlock()
+}
+
+func currentRevocationStore() RevocationStore {
+	revocationMu.RLock()
+	defer revocationMu.RUnlock()
+	return revocationStore
+}
+
+func BlacklistToken(jti string, exp time.Time) error {
+	return currentRevocationStore().Revoke(context.Background(), jti, exp)
+}
+
+func IsTokenBlacklisted(jti string) (bool, error) {
+	return currentRevocationStore().IsRevoked(context.Background(), jti)
+}
+
+// StartRevocationSweeper menghapus JTI yang sudah expired secara berkala.
+// Sweeper berhenti ketika ctx dibatalkan.
+func StartRevocationSweeper(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
 
-	return true
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purged, err := currentRevocationStore().PurgeExpired(ctx)
+				if err != nil {
+					log.Println("revocation sweeper error:", err)
+					continue
+				}
+				if purged > 0 {
+					log.Printf("revocation sweeper: %d expired token removed\n", purged)
+				}
+			}
+		}
+	}()
 }
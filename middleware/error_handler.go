@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"PROJECTUAS_BE/app/repository"
+	"PROJECTUAS_BE/app/response"
+	"PROJECTUAS_BE/app/validation"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrorHandler adalah satu-satunya tempat error diubah menjadi response:
+//   - validation.Errors → 422 dengan daftar field
+//   - *fiber.Error      → status dan pesan dari handler
+//   - error domain repository (not found, conflict, forbidden, invalid) → 404 / 409 / 403 / 400
+//   - selain itu → 500 tanpa teks error asli, detailnya hanya di log bersama request id
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var (
+		fieldErrs validation.Errors
+		fiberErr  *fiber.Error
+		domainErr *repository.DomainError
+	)
+
+	switch {
+	case errors.As(err, &fieldErrs):
+		return response.Error(c, fiber.StatusUnprocessableEntity, "Validation failed", fieldErrs)
+
+	case errors.As(err, &fiberErr):
+		if fiberErr.Code >= fiber.StatusInternalServerError {
+			log.Printf("[%s] %s %s: %v\n", response.RequestID(c), c.Method(), c.Path(), err)
+		}
+		return response.Error(c, fiberErr.Code, fiberErr.Message, nil)
+
+	case errors.As(err, &domainErr):
+		return response.Error(c, domainStatus(domainErr.Kind), domainErr.Message, nil)
+
+	case errors.Is(err, sql.ErrNoRows):
+		return response.Error(c, fiber.StatusNotFound, "Resource not found", nil)
+	}
+
+	log.Printf("[%s] %s %s: %v\n", response.RequestID(c), c.Method(), c.Path(), err)
+	return response.Error(c, fiber.StatusInternalServerError, "Internal server error", nil)
+}
+
+func domainStatus(kind repository.ErrorKind) int {
+	switch kind {
+	case repository.KindNotFound:
+		return fiber.StatusNotFound
+	case repository.KindConflict:
+		return fiber.StatusConflict
+	case repository.KindForbidden:
+		return fiber.StatusForbidden
+	case repository.KindInvalid:
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
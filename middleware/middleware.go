@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	model "PROJECTUAS_BE/app/Model"
 	"fmt"
 	"strings"
 
@@ -12,34 +13,33 @@ func AuthRequired() fiber.Handler {
 
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "missing token",
-			})
+			return fiber.NewError(fiber.StatusUnauthorized, "missing token")
 		}
 
 		parts := strings.Split(authHeader, " ")
 		// Contoh: "Bearer tokenxxxxx"
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "invalid token format",
-			})
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid token format")
 		}
 
-		tokenStr := parts[1]
+		// Parse JWT
+		claims, err := ParseToken(parts[1])
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid token")
+		}
 
-		// CEK TOKEN BLACKLIST
-		if IsTokenBlacklisted(tokenStr) { // tidak perlu prefix middleware.
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "token has been revoked",
-			})
+		// token tanpa jti tidak bisa di-revoke, jadi tidak diterima
+		if claims.ID == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid token")
 		}
 
-		// Parse JWT
-		claims, err := ParseToken(parts[1])
+		// CEK TOKEN BLACKLIST
+		revoked, err := IsTokenBlacklisted(claims.ID)
 		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "invalid token",
-			})
+			return fiber.NewError(fiber.StatusServiceUnavailable, "failed to check token revocation")
+		}
+		if revoked {
+			return fiber.NewError(fiber.StatusUnauthorized, "token has been revoked")
 		}
 
 		// DEBUG: memastikan claims terbaca
@@ -56,6 +56,13 @@ func AuthRequired() fiber.Handler {
 	}
 }
 
+// Nama role sesuai kolom roles.name, didefinisikan di model agar bisa dipakai repository
+const (
+	RoleAdmin    = model.RoleAdmin
+	RoleStudent  = model.RoleStudent
+	RoleLecturer = model.RoleLecturer
+)
+
 func RequireRole(roles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claims := c.Locals("claims").(*Claims)
@@ -66,9 +73,7 @@ func RequireRole(roles ...string) fiber.Handler {
 			}
 		}
 
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "forbidden, role not allowed",
-		})
+		return fiber.NewError(fiber.StatusForbidden, "forbidden, role not allowed")
 	}
 }
 
@@ -82,8 +87,6 @@ func RequirePermission(permission string) fiber.Handler {
 			}
 		}
 
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "permission denied",
-		})
+		return fiber.NewError(fiber.StatusForbidden, "permission denied")
 	}
 }
@@ -0,0 +1,32 @@
+-- Permission yang dipakai oleh middleware.RequirePermission di routes.SetupRoutes
+INSERT INTO permissions (name, resource, action) VALUES
+    ('achievement:create', 'achievement', 'create'),
+    ('achievement:read',   'achievement', 'read'),
+    ('achievement:update', 'achievement', 'update'),
+    ('achievement:delete', 'achievement', 'delete'),
+    ('achievement:verify', 'achievement', 'verify'),
+    ('user:manage',        'user',        'manage')
+ON CONFLICT (name) DO NOTHING;
+
+-- admin: semua permission
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- mahasiswa: kelola prestasi sendiri
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r JOIN permissions p
+  ON p.name IN ('achievement:create', 'achievement:read', 'achievement:update', 'achievement:delete')
+WHERE r.name = 'mahasiswa'
+ON CONFLICT DO NOTHING;
+
+-- dosen wali: baca dan verifikasi prestasi
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r JOIN permissions p
+  ON p.name IN ('achievement:read', 'achievement:verify')
+WHERE r.name = 'dosen'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,7 @@
+-- Status yang dipakai state machine prestasi (service.AchievementLifecycle)
+ALTER TABLE achievement_references DROP CONSTRAINT IF EXISTS achievement_references_status_check;
+ALTER TABLE achievement_references
+    ADD CONSTRAINT achievement_references_status_check
+    CHECK (status IN ('draft', 'submitted', 'verified', 'rejected', 'deleted'));
+
+ALTER TABLE achievement_references ALTER COLUMN status SET DEFAULT 'draft';
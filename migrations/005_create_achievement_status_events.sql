@@ -0,0 +1,25 @@
+-- Log append-only setiap perubahan status prestasi (tidak pernah di-UPDATE / DELETE)
+CREATE TABLE IF NOT EXISTS achievement_status_events (
+    id                   BIGSERIAL PRIMARY KEY,
+    reference_id         UUID NOT NULL REFERENCES achievement_references(id),
+    mongo_achievement_id VARCHAR(64) NOT NULL,
+    actor_id             UUID REFERENCES users(id) ON DELETE SET NULL,
+    from_status          VARCHAR(20),
+    to_status            VARCHAR(20) NOT NULL,
+    note                 TEXT,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_events_mongo_id ON achievement_status_events (mongo_achievement_id, id);
+
+-- Backfill dari status terakhir yang sudah ada sebelum tabel ini dibuat
+INSERT INTO achievement_status_events (reference_id, mongo_achievement_id, actor_id, from_status, to_status, created_at)
+SELECT ar.id, ar.mongo_achievement_id, NULL, 'draft', 'submitted', ar.submitted_at
+FROM achievement_references ar
+WHERE ar.submitted_at IS NOT NULL;
+
+INSERT INTO achievement_status_events (reference_id, mongo_achievement_id, actor_id, from_status, to_status, note, created_at)
+SELECT ar.id, ar.mongo_achievement_id, ar.verified_by, 'submitted', ar.status, ar.rejection_reason, ar.verified_at
+FROM achievement_references ar
+WHERE ar.status IN ('verified', 'rejected')
+  AND ar.verified_at IS NOT NULL;
@@ -0,0 +1,38 @@
+-- Transactional outbox: perubahan yang harus diterapkan ke MongoDB,
+-- ditulis dalam transaksi yang sama dengan achievement_references
+CREATE TABLE IF NOT EXISTS achievement_outbox (
+    id                   BIGSERIAL PRIMARY KEY,
+    idempotency_key      VARCHAR(128) NOT NULL UNIQUE,
+    mongo_achievement_id VARCHAR(64) NOT NULL,
+    event_type           VARCHAR(64) NOT NULL,
+    payload              JSONB NOT NULL,
+    attempts             INT NOT NULL DEFAULT 0,
+    last_error           TEXT,
+    next_attempt_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_at         TIMESTAMPTZ,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending
+    ON achievement_outbox (next_attempt_at, id)
+    WHERE processed_at IS NULL;
+
+-- Sinkronkan status yang sudah ada ke MongoDB sekali saat migrasi
+INSERT INTO achievement_outbox (idempotency_key, mongo_achievement_id, event_type, payload)
+SELECT
+    'status-event:' || e.id,
+    e.mongo_achievement_id,
+    'achievement.status_sync',
+    jsonb_build_object(
+        'seq', e.id,
+        'status', e.to_status,
+        'occurred_at', e.created_at,
+        'verified_by', CASE WHEN e.to_status IN ('verified', 'rejected') THEN e.actor_id END,
+        'rejection_note', CASE WHEN e.to_status = 'rejected' THEN e.note END
+    )
+FROM achievement_status_events e
+WHERE e.id = (
+    SELECT MAX(id) FROM achievement_status_events
+    WHERE mongo_achievement_id = e.mongo_achievement_id
+)
+ON CONFLICT (idempotency_key) DO NOTHING;
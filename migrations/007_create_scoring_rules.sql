@@ -0,0 +1,22 @@
+-- Rubrik poin prestasi. Kolom NULL berarti "semua nilai";
+-- aturan yang paling spesifik (kolom terisi paling banyak) dipakai.
+CREATE TABLE IF NOT EXISTS scoring_rules (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    achievement_type  VARCHAR(50) NOT NULL,
+    competition_level VARCHAR(50),
+    rank              INT CHECK (rank > 0),
+    medal_type        VARCHAR(50),
+    points            INT NOT NULL CHECK (points >= 0),
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_scoring_rules_criteria ON scoring_rules (
+    achievement_type,
+    COALESCE(competition_level, ''),
+    COALESCE(rank, 0),
+    COALESCE(medal_type, '')
+);
+
+-- Poin dihitung server saat verifikasi, disalin ke MongoDB oleh outbox worker
+ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS points INT;
@@ -0,0 +1,6 @@
+-- Mahasiswa bisa menampilkan prestasi verified di portfolio publik (opt-in)
+ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS is_public BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_public
+    ON achievement_references (student_id)
+    WHERE is_public AND status = 'verified';
@@ -0,0 +1,8 @@
+-- Dosen bisa meng-claim prestasi submitted yang sedang direview (lease, lihat LecturesService.ClaimLease)
+ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS claimed_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE achievement_references ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP;
+
+-- antrean review: prestasi submitted, yang paling lama menunggu lebih dulu
+CREATE INDEX IF NOT EXISTS idx_achievement_references_queue
+    ON achievement_references (student_id, submitted_at)
+    WHERE status = 'submitted';
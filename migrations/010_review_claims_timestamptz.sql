@@ -0,0 +1,2 @@
+-- claimed_at dibandingkan dengan waktu dari aplikasi (lease claim), simpan dengan zona waktu
+ALTER TABLE achievement_references ALTER COLUMN claimed_at TYPE TIMESTAMPTZ;
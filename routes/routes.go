@@ -1,60 +1,238 @@
 package routes
 
 import (
+	"PROJECTUAS_BE/app/response"
 	"PROJECTUAS_BE/app/service"
 	"PROJECTUAS_BE/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupRoutes(app *fiber.App, Userservice *service.UserService, Studentservice *service.Studentservice, AchieveService *service.AchievementService, LectureService *service.LecturesService, ReportService *service.ReportService, AuthService *service.AuthService) {
+// Endpoint adalah satu baris pada tabel route
+type Endpoint struct {
+	Method     string        `json:"method"`
+	Path       string        `json:"path"`
+	Roles      []string      `json:"roles"`
+	Permission string        `json:"permission,omitempty"`
+	Public     bool          `json:"public"`
+	Handler    fiber.Handler `json:"-"`
+}
+
+// RouteGroup mengelompokkan endpoint dengan prefix dan role yang sama.
+// Roles kosong + Public berarti endpoint bisa diakses tanpa login.
+// Root berarti didaftarkan langsung di app, tanpa prefix /api.
+type RouteGroup struct {
+	Prefix    string
+	Roles     []string
+	Public    bool
+	Root      bool
+	Endpoints []Endpoint
+}
+
+var allRoles = []string{middleware.RoleAdmin, middleware.RoleStudent, middleware.RoleLecturer}
+
+func SetupRoutes(app *fiber.App, Userservice *service.UserService, Studentservice *service.Studentservice, AchieveService *service.AchievementService, LectureService *service.LecturesService, ReportService *service.ReportService, AuthService *service.AuthService, PermissionService *service.PermissionService, ConsistencyService *service.ConsistencyService, ScoringService *service.ScoringService, PortfolioService *service.PortfolioService) {
 	api := app.Group("/api")
 
-	// authentication Route
-	api.Post("/login", AuthService.Login)
-	api.Get("/Getprofile", middleware.AuthRequired(), AuthService.GetProfile)
-	api.Post("/logout", middleware.AuthRequired(), AuthService.Logout)
-	// authentication route
-
-	// users route
-	api.Use(middleware.AuthRequired()) // melindungi agar hanya admin yang bisa mengakses
-	api.Get("/users", Userservice.GetAllUsers)
-	api.Get("/users/:id", Userservice.GetUsersByID)
-	api.Post("/users", Userservice.CreateUser)
-	api.Put("/users/:id", Userservice.UpdateUserByID)
-	api.Delete("/users/:id", Userservice.DeleteUserByID)
-
-	// achievement
-	api.Get("/achievements", AchieveService.GetAllAchievements)
-	api.Get("/achievements/:id", AchieveService.GetAchievementsByID)
-
-	api.Use(middleware.AuthRequired()) // melindungi agar hanya mahasiswa yang bisa mengakses
-	api.Post("/achievements", AchieveService.CreateAchievements)
-	api.Put("/achievements/:id", AchieveService.UpdateAchievement)
-	api.Delete("/achievements/:id", AchieveService.DeleteAchievement)
-	api.Post("/achievements/:id/submit", Studentservice.SubmitAchievement)
-
-	api.Use(middleware.AuthRequired()) // melindungi agar hanya dosen wali yang bisa mengakses
-	api.Post("/achievements/:id/verify", LectureService.VerifyAchievement)
-	api.Post("/achievements/:id/Reject", LectureService.RejectAchievement)
-
-	api.Post("/achievements/:id/history", LectureService.GetHistory)
-
-	api.Post("/achievements/:id/Attachment", AchieveService.UploadAttachments)
-
-	// students and Lecturers
-	api.Get("/student/:id", Studentservice.GetStudent)
-	api.Get("/student", Studentservice.GetAllStudents)
-
-	api.Use(middleware.AuthRequired())
-	api.Get("/student/:id/achievement", AchieveService.GetStudentAchievements)
-	api.Put("student/:id/advisor", Studentservice.UpdateAdvisor)
-	// lectures
-	api.Get("/lecturers", LectureService.GetLectures)
-	api.Get("/lecturers/:id/advisees", LectureService.Getadvisees)
-
-	// report and analytics
-	api.Use(middleware.AuthRequired())
-	api.Get("/reports/statics", ReportService.GetStatics)
-	api.Get("/reports/student/:id", ReportService.GetStudentReport)
+	var groups []RouteGroup
+	listRoutes := func(c *fiber.Ctx) error {
+		return routeList(c, "/api", groups)
+	}
+
+	groups = []RouteGroup{
+		// authentication route
+		{
+			Prefix: "",
+			Public: true,
+			Endpoints: []Endpoint{
+				{Method: fiber.MethodPost, Path: "/login", Handler: AuthService.Login},
+				{Method: fiber.MethodPost, Path: "/refresh", Handler: AuthService.Refresh},
+			},
+		},
+
+		// tanpa login: prestasi verified yang dipublikasikan mahasiswa, link verifikasi dan signed URL lampiran.
+		// Dibagikan ke pihak luar, jadi path-nya /public/... bukan /api/public/...
+		{
+			Prefix: "/public",
+			Public: true,
+			Root:   true,
+			Endpoints: []Endpoint{
+				{Method: fiber.MethodGet, Path: "/achievements/:id", Handler: AchieveService.GetPublicAchievement},
+				{Method: fiber.MethodGet, Path: "/portfolio/:username", Handler: PortfolioService.GetPortfolio},
+				{Method: fiber.MethodGet, Path: "/verify/:id", Handler: PortfolioService.VerifyAchievementLink},
+				{Method: fiber.MethodGet, Path: "/attachments/:id/:attachmentId", Handler: AchieveService.DownloadSignedAttachment},
+			},
+		},
+
+		// semua user yang sudah login
+		{
+			Prefix: "",
+			Roles:  allRoles,
+			Endpoints: []Endpoint{
+				{Method: fiber.MethodGet, Path: "/Getprofile", Handler: AuthService.GetProfile},
+				{Method: fiber.MethodPost, Path: "/logout", Handler: AuthService.Logout},
+
+				// achievement
+				{Method: fiber.MethodGet, Path: "/achievements", Permission: "achievement:read", Handler: AchieveService.GetAllAchievements},
+				{Method: fiber.MethodGet, Path: "/achievements/search", Permission: "achievement:read", Handler: AchieveService.SearchAchievements},
+				{Method: fiber.MethodGet, Path: "/achievements/:id", Permission: "achievement:read", Handler: AchieveService.GetAchievementsByID},
+				{Method: fiber.MethodGet, Path: "/achievements/:id/history", Permission: "achievement:read", Handler: LectureService.GetHistory},
+				{Method: fiber.MethodGet, Path: "/achievements/:id/attachments/:attachmentId", Permission: "achievement:read", Handler: AchieveService.DownloadAttachment},
+				{Method: fiber.MethodGet, Path: "/achievements/:id/attachments/:attachmentId/url", Permission: "achievement:read", Handler: AchieveService.GetAttachmentURL},
+				{Method: fiber.MethodGet, Path: "/achievements/:id/attachments/:attachmentId/preview", Permission: "achievement:read", Handler: AchieveService.GetAttachmentPreview},
+				// hanya pemilik prestasi, dicek di service
+				{Method: fiber.MethodPut, Path: "/achievements/:id/attachments/:attachmentId", Permission: "achievement:update", Handler: AchieveService.ReplaceAttachment},
+				{Method: fiber.MethodDelete, Path: "/achievements/:id/attachments/:attachmentId", Permission: "achievement:update", Handler: AchieveService.DeleteAttachment},
+
+				// lectures
+				{Method: fiber.MethodGet, Path: "/lecturers", Handler: LectureService.GetLectures},
+				{Method: fiber.MethodGet, Path: "/lecturers/:id/advisees", Handler: LectureService.Getadvisees},
+
+				// report and analytics
+				{Method: fiber.MethodGet, Path: "/reports/statics", Permission: "achievement:read", Handler: ReportService.GetStatics},
+				{Method: fiber.MethodGet, Path: "/reports/student/:id", Permission: "achievement:read", Handler: ReportService.GetStudentReport},
+				{Method: fiber.MethodGet, Path: "/reports/leaderboard", Permission: "achievement:read", Handler: ReportService.GetLeaderboard},
+
+				// meta: daftar endpoint beserta role yang boleh mengakses
+				{Method: fiber.MethodGet, Path: "/meta/routes", Handler: listRoutes},
+			},
+		},
+
+		// hanya admin
+		{
+			Prefix: "/admin",
+			Roles:  []string{middleware.RoleAdmin},
+			Endpoints: []Endpoint{
+				// users
+				{Method: fiber.MethodGet, Path: "/users", Permission: "user:manage", Handler: Userservice.GetAllUsers},
+				{Method: fiber.MethodGet, Path: "/users/:id", Permission: "user:manage", Handler: Userservice.GetUsersByID},
+				{Method: fiber.MethodPost, Path: "/users", Permission: "user:manage", Handler: Userservice.CreateUser},
+				{Method: fiber.MethodPut, Path: "/users/:id", Permission: "user:manage", Handler: Userservice.UpdateUserByID},
+				{Method: fiber.MethodDelete, Path: "/users/:id", Permission: "user:manage", Handler: Userservice.DeleteUserByID},
+
+				// role permissions
+				{Method: fiber.MethodGet, Path: "/permissions", Permission: "user:manage", Handler: PermissionService.GetAllPermissions},
+				{Method: fiber.MethodGet, Path: "/roles/:id/permissions", Permission: "user:manage", Handler: PermissionService.GetRolePermissions},
+				{Method: fiber.MethodPost, Path: "/roles/:id/permissions", Permission: "user:manage", Handler: PermissionService.GrantPermission},
+				{Method: fiber.MethodDelete, Path: "/roles/:id/permissions/:permissionId", Permission: "user:manage", Handler: PermissionService.RevokePermission},
+
+				// students
+				{Method: fiber.MethodGet, Path: "/students", Handler: Studentservice.GetAllStudents},
+				{Method: fiber.MethodGet, Path: "/students/:id", Handler: Studentservice.GetStudent},
+				{Method: fiber.MethodPut, Path: "/students/:id/advisor", Permission: "user:manage", Handler: Studentservice.UpdateAdvisor},
+
+				// sinkronisasi MongoDB ↔ PostgreSQL
+				{Method: fiber.MethodPost, Path: "/consistency/check", Permission: "user:manage", Handler: ConsistencyService.Check},
+
+				// rubrik poin prestasi
+				{Method: fiber.MethodGet, Path: "/scoring-rules", Permission: "user:manage", Handler: ScoringService.GetRules},
+				{Method: fiber.MethodPost, Path: "/scoring-rules", Permission: "user:manage", Handler: ScoringService.CreateRule},
+				{Method: fiber.MethodPost, Path: "/scoring-rules/recalculate", Permission: "user:manage", Handler: ScoringService.RecalculatePoints},
+				{Method: fiber.MethodPut, Path: "/scoring-rules/:id", Permission: "user:manage", Handler: ScoringService.UpdateRule},
+				{Method: fiber.MethodDelete, Path: "/scoring-rules/:id", Permission: "user:manage", Handler: ScoringService.DeleteRule},
+			},
+		},
+
+		// hanya mahasiswa
+		{
+			Prefix: "/student",
+			Roles:  []string{middleware.RoleStudent},
+			Endpoints: []Endpoint{
+				{Method: fiber.MethodGet, Path: "/achievements", Permission: "achievement:read", Handler: AchieveService.GetStudentAchievements},
+				{Method: fiber.MethodPost, Path: "/achievements", Permission: "achievement:create", Handler: AchieveService.CreateAchievements},
+				{Method: fiber.MethodPut, Path: "/achievements/:id", Permission: "achievement:update", Handler: AchieveService.UpdateAchievement},
+				{Method: fiber.MethodDelete, Path: "/achievements/:id", Permission: "achievement:delete", Handler: AchieveService.DeleteAchievement},
+				{Method: fiber.MethodPost, Path: "/achievements/:id/submit", Permission: "achievement:update", Handler: Studentservice.SubmitAchievement},
+				{Method: fiber.MethodPost, Path: "/achievements/:id/attachments", Permission: "achievement:update", Handler: AchieveService.UploadAttachments},
+				{Method: fiber.MethodPut, Path: "/achievements/:id/visibility", Permission: "achievement:update", Handler: AchieveService.SetAchievementVisibility},
+				{Method: fiber.MethodGet, Path: "/achievements/:id/verification-link", Permission: "achievement:read", Handler: PortfolioService.GetVerificationLink},
+			},
+		},
+
+		// hanya dosen wali
+		{
+			Prefix: "/lecturer",
+			Roles:  []string{middleware.RoleLecturer},
+			Endpoints: []Endpoint{
+				{Method: fiber.MethodPost, Path: "/achievements/:id/verify", Permission: "achievement:verify", Handler: LectureService.VerifyAchievement},
+				{Method: fiber.MethodPost, Path: "/achievements/:id/reject", Permission: "achievement:verify", Handler: LectureService.RejectAchievement},
+				{Method: fiber.MethodGet, Path: "/queue", Permission: "achievement:verify", Handler: LectureService.GetReviewQueue},
+				{Method: fiber.MethodPost, Path: "/queue/decisions", Permission: "achievement:verify", Handler: LectureService.DecideBatch},
+				{Method: fiber.MethodPost, Path: "/queue/:id/claim", Permission: "achievement:verify", Handler: LectureService.ClaimReview},
+				{Method: fiber.MethodDelete, Path: "/queue/:id/claim", Permission: "achievement:verify", Handler: LectureService.ReleaseReview},
+				{Method: fiber.MethodGet, Path: "/students", Handler: Studentservice.GetAllStudents},
+				{Method: fiber.MethodGet, Path: "/students/:id", Handler: Studentservice.GetStudent},
+			},
+		},
+	}
+
+	for _, group := range groups {
+		router := fiber.Router(api)
+		if group.Root {
+			router = app
+		}
+		registerGroup(router, group)
+	}
+}
+
+// registerGroup memasang guard di setiap endpoint, bukan di Group: middleware Group Fiber
+// dicocokkan per prefix, sehingga guard "/lecturer" juga akan berlaku untuk "/lecturers".
+func registerGroup(api fiber.Router, group RouteGroup) {
+	var guards []fiber.Handler
+	if !group.Public {
+		guards = []fiber.Handler{middleware.AuthRequired(), middleware.RequireRole(group.Roles...)}
+	}
+
+	for _, e := range group.Endpoints {
+		handlers := append([]fiber.Handler{}, guards...)
+		if e.Permission != "" {
+			handlers = append(handlers, middleware.RequirePermission(e.Permission))
+		}
+		handlers = append(handlers, e.Handler)
+
+		api.Add(e.Method, group.Prefix+e.Path, handlers...)
+	}
+}
+
+func routeList(c *fiber.Ctx, basePath string, groups []RouteGroup) error {
+	// ?role=dosen → hanya endpoint yang bisa dipanggil role tersebut
+	role := c.Query("role")
+
+	result := []Endpoint{}
+	for _, group := range groups {
+		if role != "" && !group.Public && !containsRole(group.Roles, role) {
+			continue
+		}
+
+		roles := group.Roles
+		if roles == nil {
+			roles = []string{}
+		}
+
+		base := basePath
+		if group.Root {
+			base = ""
+		}
+
+		for _, e := range group.Endpoints {
+			result = append(result, Endpoint{
+				Method:     e.Method,
+				Path:       base + group.Prefix + e.Path,
+				Roles:      roles,
+				Permission: e.Permission,
+				Public:     group.Public,
+			})
+		}
+	}
+
+	return response.OK(c, "", result)
+}
+
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
 }